@@ -70,8 +70,10 @@ type Telemetry struct {
 	LRtempM            float64                `protobuf:"fixed64,44,opt,name=l_rtemp_m,json=lRtempM,proto3" json:"l_rtemp_m,omitempty"`
 	RRtempM            float64                `protobuf:"fixed64,45,opt,name=r_rtemp_m,json=rRtempM,proto3" json:"r_rtemp_m,omitempty"`
 	TickTime           *timestamppb.Timestamp `protobuf:"bytes,46,opt,name=tick_time,json=tickTime,proto3" json:"tick_time,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// Extra IBT channels picked in the channel manifest, keyed by IBT variable name
+	Channels      map[string]float64 `protobuf:"bytes,47,rep,name=channels,proto3" json:"channels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Telemetry) Reset() {
@@ -426,6 +428,13 @@ func (x *Telemetry) GetTickTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Telemetry) GetChannels() map[string]float64 {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
 type TelemetryBatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Records       []*Telemetry           `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
@@ -506,7 +515,7 @@ var File_telemetry_proto protoreflect.FileDescriptor
 
 const file_telemetry_proto_rawDesc = "" +
 	"\n" +
-	"\x0ftelemetry.proto\x12\x06pubSub\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\v\n" +
+	"\x0ftelemetry.proto\x12\x06pubSub\x1a\x1fgoogle/protobuf/timestamp.proto\"\xff\v\n" +
 	"\tTelemetry\x12\x15\n" +
 	"\x06lap_id\x18\x01 \x01(\tR\x05lapId\x12\x14\n" +
 	"\x05speed\x18\x02 \x01(\x01R\x05speed\x12 \n" +
@@ -569,7 +578,11 @@ const file_telemetry_proto_rawDesc = "" +
 	"\tr_ftemp_m\x18+ \x01(\x01R\arFtempM\x12\x1a\n" +
 	"\tl_rtemp_m\x18, \x01(\x01R\alRtempM\x12\x1a\n" +
 	"\tr_rtemp_m\x18- \x01(\x01R\arRtempM\x127\n" +
-	"\ttick_time\x18. \x01(\v2\x1a.google.protobuf.TimestampR\btickTime\"\xce\x01\n" +
+	"\ttick_time\x18. \x01(\v2\x1a.google.protobuf.TimestampR\btickTime\x12;\n" +
+	"\bchannels\x18/ \x03(\v2\x1f.pubSub.Telemetry.ChannelsEntryR\bchannels\x1a;\n" +
+	"\rChannelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xce\x01\n" +
 	"\x0eTelemetryBatch\x12+\n" +
 	"\arecords\x18\x01 \x03(\v2\x11.pubSub.TelemetryR\arecords\x12\x19\n" +
 	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12\x1d\n" +
@@ -590,21 +603,23 @@ func file_telemetry_proto_rawDescGZIP() []byte {
 	return file_telemetry_proto_rawDescData
 }
 
-var file_telemetry_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_telemetry_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_telemetry_proto_goTypes = []any{
 	(*Telemetry)(nil),             // 0: pubSub.Telemetry
 	(*TelemetryBatch)(nil),        // 1: pubSub.TelemetryBatch
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	nil,                           // 2: pubSub.Telemetry.ChannelsEntry
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
 }
 var file_telemetry_proto_depIdxs = []int32{
-	2, // 0: pubSub.Telemetry.tick_time:type_name -> google.protobuf.Timestamp
-	0, // 1: pubSub.TelemetryBatch.records:type_name -> pubSub.Telemetry
-	2, // 2: pubSub.TelemetryBatch.timestamp:type_name -> google.protobuf.Timestamp
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3, // 0: pubSub.Telemetry.tick_time:type_name -> google.protobuf.Timestamp
+	2, // 1: pubSub.Telemetry.channels:type_name -> pubSub.Telemetry.ChannelsEntry
+	0, // 2: pubSub.TelemetryBatch.records:type_name -> pubSub.Telemetry
+	3, // 3: pubSub.TelemetryBatch.timestamp:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_telemetry_proto_init() }
@@ -618,7 +633,7 @@ func file_telemetry_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_telemetry_proto_rawDesc), len(file_telemetry_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
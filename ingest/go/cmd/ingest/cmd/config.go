@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the ingest configuration",
+	Long: `Configuration is read from the defaults, then the config file (--config, INGEST_CONFIG or the default path),
+	then environment variables, then --set KEY=VALUE overrides. Config file keys are the environment variable
+	names in lower case, e.g. batch_size_records: 8000.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration with secrets redacted",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"Key", "Value", "Source"})
+		for _, setting := range cfg.Settings() {
+			t.AppendRow(table.Row{setting.Key, setting.Value, setting.Origin})
+		}
+
+		t.Render()
+
+		if cfg.File == "" {
+			fmt.Println("No config file loaded")
+		} else {
+			fmt.Printf("Config file: %s\n", cfg.File)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/dashboard"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/worker"
+)
+
+// startDashboard feeds the live dashboard from the pool metrics until the
+// returned stop func is called.
+func startDashboard(pool *worker.WorkerPool, workerCount, expectedFiles int) func() {
+	dash := dashboard.NewDashboard(workerCount)
+	dash.Start()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			updateDashboard(dash, pool.GetMetrics(), expectedFiles)
+
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		updateDashboard(dash, pool.GetMetrics(), expectedFiles)
+		dash.Stop()
+	}
+}
+
+type workerDashboard interface {
+	UpdateWorker(id int, metrics *dashboard.WorkerMetrics)
+	UpdateSummary(summary dashboard.Summary)
+}
+
+func updateDashboard(dash workerDashboard, metrics worker.PoolMetrics, expectedFiles int) {
+	dash.UpdateSummary(dashboard.Summary{
+		FilesProcessed: metrics.TotalFilesProcessed,
+		FilesExpected:  expectedFiles,
+		QueueDepth:     metrics.QueueDepth,
+		Errors:         metrics.TotalErrors,
+		BreakerTrips:   metrics.CircuitBreakerEvents,
+		StartTime:      metrics.StartTime,
+	})
+
+	for _, wm := range metrics.WorkerMetrics {
+		// Records already finished plus what the current file has sent so far
+		records := int(wm.TotalRecords)
+		rate := wm.ProcessingRate
+		if wm.Status == "PROCESSING" {
+			records += wm.RecordsSent
+			if elapsed := time.Since(wm.LastActivity).Seconds(); elapsed > 0 {
+				rate = float64(wm.RecordsSent) / elapsed
+			}
+		}
+
+		queueUsage := 0
+		if wm.PublishQueueCap > 0 {
+			queueUsage = wm.PublishQueueSize * 100 / wm.PublishQueueCap
+		}
+
+		dash.UpdateWorker(wm.WorkerID, &dashboard.WorkerMetrics{
+			ID:                wm.WorkerID,
+			WorkerID:          wm.WorkerID,
+			FilesProcessed:    wm.FilesProcessed,
+			CurrentFile:       wm.CurrentFile,
+			TotalRecords:      records,
+			RecordsPerSec:     int(rate),
+			TotalBatches:      int(wm.TotalBatches),
+			BatchesSent:       wm.BatchesSent,
+			ThroughputMBps:    rate * 512 / 1024 / 1024, // ~512 bytes per record, as the progress display estimates
+			AvgProcessingTime: wm.AvgTimePerFile,
+			QueueSize:         wm.PublishQueueSize,
+			QueueCapacity:     wm.PublishQueueCap,
+			QueueUsage:        queueUsage,
+			BatchSize:         wm.BatchSize,
+			Status:            wm.Status,
+			CircuitBreaker:    wm.CircuitBreakerOpen,
+			BreakerState:      wm.BreakerState,
+			FailedBatches:     wm.FailedBatches,
+			PersistedBatches:  wm.PersistedBatches,
+			Errors:            wm.ErrorCount,
+			LastUpdate:        time.Now(),
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/export"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDir      string
+	exportFormat   string
+	exportChannels []string
+	exportSessions []string
+	exportLaps     string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <file.ibt|folder>",
+	Short: "Convert IBT files to CSV, Parquet or NDJSON without the ingest stack",
+	Long: `Read IBT files the same way ingest does and write each session group to its own file,
+	named <SubSessionID>_<track>.<format>, in the output folder. Nothing is sent to RabbitMQ or QuestDB.
+
+	Channels are Telemetry fields (speed, throttle, lap_dist_pct, ...) or any IBT variable name,
+	sessions are session numbers or types (0, race, "lone qualify") and laps take ranges (3,5-7).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := export.Options{
+			Dir:      exportDir,
+			Format:   strings.ToLower(exportFormat),
+			Sessions: exportSessions,
+		}
+
+		if exportLaps != "" {
+			laps, err := export.ParseLaps(exportLaps)
+			if err != nil {
+				return err
+			}
+			opts.Laps = laps
+		}
+
+		columns, extra, err := export.Columns(exportChannels)
+		if err != nil {
+			return err
+		}
+
+		if err := opts.Validate(); err != nil {
+			return err
+		}
+
+		files, err := ibtFiles(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		fp, err := processing.NewFileProcessor(cfg, 0, nil)
+		if err != nil {
+			return err
+		}
+		fp.AddChannels(extra...)
+		fp.SetSinkFactory(func(sessionID, trackName string, sessionTime time.Time) (sink.Sink, error) {
+			return export.New(opts, columns, sessionID, trackName)
+		})
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		failed := 0
+		for _, file := range files {
+			result, err := fp.ProcessFile(ctx, filepath.Dir(file.path), file.entry)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				fmt.Printf("EXPORT: Skipped %s: %v\n", file.entry.Name(), err)
+				failed++
+				continue
+			}
+
+			rows := 0
+			if result.MessagingMetrics != nil {
+				rows = result.MessagingMetrics.TotalRecords
+			}
+			fmt.Printf("EXPORT: %s -> %d rows (session %s, %s)\n", file.entry.Name(), rows, result.SessionID, result.TrackName)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d files could not be exported\nAction: Check the messages above, files still being written cannot be read yet", failed, len(files))
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportDir, "out", "o", "./export", "folder the exported files are written to")
+	exportCmd.Flags().StringVar(&exportFormat, "format", export.FormatCSV, "output format: csv, parquet or ndjson")
+	exportCmd.Flags().StringSliceVarP(&exportChannels, "channels", "c", nil, "channels to export, defaults to every Telemetry field")
+	exportCmd.Flags().StringSliceVarP(&exportSessions, "sessions", "s", nil, "session numbers or types to export, defaults to all")
+	exportCmd.Flags().StringVarP(&exportLaps, "laps", "l", "", "laps to export, e.g. 3,5-7")
+
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+)
+
+type ibtFile struct {
+	path  string
+	entry fs.DirEntry
+}
+
+// ibtFiles accepts a single IBT file or a folder of them
+func ibtFiles(path string) ([]ibtFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w\nAction: Pass an IBT file or the IRacing telemetry folder", path, err)
+	}
+
+	if !info.IsDir() {
+		return []ibtFile{{path: path, entry: fs.FileInfoToDirEntry(info)}}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list %s: %w\nAction: Check the folder permissions", path, err)
+	}
+
+	var files []ibtFile
+	for _, entry := range entries {
+		if !entry.IsDir() && processing.IsIBTFile(entry.Name()) {
+			files = append(files, ibtFile{path: filepath.Join(path, entry.Name()), entry: entry})
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no IBT files found in %s\nAction: Pass the IRacing telemetry folder", path)
+	}
+	return files, nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filterFrom         string
+	filterTo           string
+	filterTracks       []string
+	filterCars         []string
+	filterSessionTypes []string
+	newestFirst        bool
+	recursive          bool
+)
+
+// addFilterFlags adds the file selection flags shared by ingest and watch
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&filterFrom, "from", "", "only sessions started on or after this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringVar(&filterTo, "to", "", "only sessions started on or before this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringSliceVar(&filterTracks, "track", nil, "only these tracks, matched against the track and config name")
+	cmd.Flags().StringSliceVar(&filterCars, "car", nil, "only these cars")
+	cmd.Flags().StringSliceVar(&filterSessionTypes, "session-type", nil, "only files with a session of these types, e.g. race or qualify")
+	cmd.Flags().BoolVar(&newestFirst, "newest-first", false, "send the most recently written files first")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "also scan the folders below each telemetry folder, same as INGEST_RECURSIVE=true")
+}
+
+// telemetryRoots joins the folders given on the command line with
+// INGEST_DIRS, checking each one exists
+func telemetryRoots(paths []string, cfg *config.Config) ([]string, error) {
+	if recursive {
+		cfg.Recursive = true
+	}
+
+	var roots []string
+	seen := make(map[string]bool)
+	for _, path := range append(append([]string(nil), paths...), cfg.TelemetryDirs...) {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("telemetry directory %s: %w\nAction: Create the directory or fix --telemetryPath / INGEST_DIRS", path, err)
+		}
+		roots = append(roots, path)
+	}
+
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no telemetry path found\nAction: Pass --telemetryPath or set INGEST_DIRS")
+	}
+	return roots, nil
+}
+
+// selectFiles applies the filter flags to directory
+func selectFiles(directory *processing.Directory, cfg *config.Config) error {
+	from, to, err := processing.ParseDateRange(filterFrom, filterTo, cfg.Timezone)
+	if err != nil {
+		return err
+	}
+
+	directory.SetNewestFirst(newestFirst)
+	return directory.SetFilter(processing.Filter{
+		From:         from,
+		To:           to,
+		Tracks:       filterTracks,
+		Cars:         filterCars,
+		SessionTypes: filterSessionTypes,
+	})
+}
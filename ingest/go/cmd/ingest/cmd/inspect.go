@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var inspectJSON bool
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <file.ibt>",
+	Short: "Show what an IBT file contains without ingesting it",
+	Long: `Print the session summary, the stub groups ingest would process (with the SubSessionID each is
+	published under), lap and record counts, the time range and every variable with its type and unit.
+
+	Run with --json for output scripts can consume.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		fp, err := processing.NewFileProcessor(cfg, 0, nil)
+		if err != nil {
+			return err
+		}
+
+		info, err := fp.Inspect(args[0])
+		if err != nil {
+			return err
+		}
+
+		if inspectJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(info)
+		}
+
+		printFileInfo(info)
+		return nil
+	},
+}
+
+func init() {
+	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "print the result as JSON")
+
+	rootCmd.AddCommand(inspectCmd)
+}
+
+func printFileInfo(info *processing.FileInfo) {
+	fmt.Printf("File:       %s\n", info.File)
+	fmt.Printf("Session ID: %s\n", info.SessionID)
+	fmt.Printf("Track:      %s %s\n", info.Track, info.TrackConfig)
+	fmt.Printf("Car:        %s\n", info.Car)
+	fmt.Printf("Driver:     %s\n", info.Driver)
+	fmt.Printf("Event:      %s\n", info.EventType)
+	if !info.SessionTime.IsZero() {
+		fmt.Printf("Recorded:   %s\n", info.SessionTime.Format(time.RFC3339))
+	}
+	fmt.Println()
+
+	sessions := table.NewWriter()
+	sessions.SetOutputMirror(os.Stdout)
+	sessions.SetTitle("Sessions")
+	sessions.AppendHeader(table.Row{"Num", "Type", "Name", "Laps", "Time"})
+	for _, s := range info.Sessions {
+		sessions.AppendRow(table.Row{s.Num, s.Type, s.Name, s.Laps, s.Time})
+	}
+	sessions.Render()
+
+	groups := table.NewWriter()
+	groups.SetOutputMirror(os.Stdout)
+	groups.SetTitle("Groups")
+	groups.AppendHeader(table.Row{"Group", "Session ID", "Stubs", "Records", "Laps", "Start", "End"})
+	for _, g := range info.Groups {
+		groups.AppendRow(table.Row{g.Index, g.SessionID, g.Stubs, g.Records, g.Laps, formatSessionTime(g.Start), formatSessionTime(g.End)})
+	}
+	groups.Render()
+
+	vars := table.NewWriter()
+	vars.SetOutputMirror(os.Stdout)
+	vars.SetTitle("Variables")
+	vars.AppendHeader(table.Row{"Name", "Type", "Count", "Unit", "Description"})
+	for _, v := range info.Variables {
+		vars.AppendRow(table.Row{v.Name, v.Type, v.Count, v.Unit, v.Desc})
+	}
+	vars.AppendFooter(table.Row{fmt.Sprintf("%d variables", len(info.Variables))})
+	vars.Render()
+}
+
+// formatSessionTime prints SessionTime seconds as h:mm:ss.mmm
+func formatSessionTime(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%06.3f", int(d.Hours()), int(d.Minutes())%60, (d % time.Minute).Seconds())
+}
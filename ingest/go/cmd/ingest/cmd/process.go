@@ -5,20 +5,18 @@ package cmd
 
 import (
 	"context"
-	"flag"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"runtime"
 	"runtime/pprof"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/metrics"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/worker"
 	"github.com/spf13/cobra"
@@ -38,58 +36,40 @@ var processCmd = &cobra.Command{
 	
 	To clean the cache of sent file run with --fresh to upload all data in the dir again`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Inside rootCmd Run with args: %v\n", args)
-		Process(args[0])
+		Process(append(telemetryPaths, args...))
 	},
 }
 
 func init() {
-	processCmd.Flags().BoolVarP(&display, "display", "d", true, "terminal display of the ingest process")
-	processCmd.Flags().StringVarP(&telemetryPath, "telemetryPath", "p", "", "path to IRacing telemetry folder")
+	processCmd.Flags().BoolVarP(&display, "display", "d", false, "live worker dashboard instead of the per-file progress bars")
+	processCmd.Flags().StringSliceVarP(&telemetryPaths, "telemetryPath", "p", nil, "path to IRacing telemetry folder (repeatable)")
 
 	processCmd.Flags().BoolVarP(&fresh, "fresh", "f", false, "will clean the local store of files that have been processed and start from fresh")
+	addFilterFlags(processCmd)
 }
 
-func Process(telemetryFolder string) {
-	var quiet = flag.Bool("quiet", false, "Disable progress display")
-	var verbose = flag.Bool("verbose", false, "Enable verbose logging")
-
+func Process(telemetryFolders []string) {
 	startTime := time.Now()
 
 	// Initialize Zap logger
 	var err error
-	if *verbose {
-		// Verbose mode: full development logging
-		logger, err = zap.NewDevelopment()
-	} else {
-		// Silent mode: errors and above
-		config := zap.NewProductionConfig()
-		config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
-		logger, err = config.Build()
-	}
+	logger, err = newLogger(verbose)
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer logger.Sync()
 
 	// Load configuration
-	cfg := config.LoadConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		logger.Fatal("Invalid configuration", zap.Error(err))
+	}
 
 	// Apply GOMAXPROCS if explicitly configured (0 means use Go's default)
 	if cfg.GoMaxProcs > 0 {
 		runtime.GOMAXPROCS(cfg.GoMaxProcs)
 	}
 
-	if os.Getenv("ENABLE_PPROF") == "true" {
-		go func() {
-			if err := http.ListenAndServe(":6060", nil); err != nil {
-				logger.Error("pprof server failed",
-					zap.Error(err),
-					zap.String("action", "Check port 6060 is not in use"))
-			}
-		}()
-	}
-
 	if cpuProfile := os.Getenv("CPU_PROFILE"); cpuProfile != "" {
 		f, err := os.Create(cpuProfile)
 		if err != nil {
@@ -109,42 +89,62 @@ func Process(telemetryFolder string) {
 	}
 
 	// Setup context and signal handling
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := signalContext()
 	defer cancel()
 
-	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-signalCh
-		cancel()
-	}()
+	startHTTPServers(ctx, cfg)
+
+	// Verify the telemetry folders exist
+	roots, err := telemetryRoots(telemetryFolders, cfg)
+	if err != nil {
+		logger.Fatal("Invalid telemetry directories", zap.Error(err))
+	}
 
-	if !strings.HasSuffix(telemetryFolder, string(filepath.Separator)) {
-		telemetryFolder += string(filepath.Separator)
+	directory := processing.NewDir(roots, cfg, logger)
+	if err := selectFiles(directory, cfg); err != nil {
+		logger.Fatal("Invalid file filter", zap.Error(err))
 	}
 
-	// Verify telemetry folder exists
-	if _, err := os.Stat(telemetryFolder); os.IsNotExist(err) {
-		logger.Fatal("Telemetry directory does not exist",
-			zap.String("path", telemetryFolder),
-			zap.String("action", "Create directory or set IBT_DATA_DIR environment variable"))
+	processed, err := ledger.Open(cfg.LedgerPath)
+	if err != nil {
+		logger.Fatal("Failed to open processed file ledger",
+			zap.Error(err),
+			zap.String("path", cfg.LedgerPath))
+	}
+
+	if fresh {
+		if err := processed.Reset(); err != nil {
+			logger.Fatal("Failed to clear processed file ledger",
+				zap.Error(err),
+				zap.String("path", cfg.LedgerPath))
+		}
 	}
 
 	// Create worker pool
 	pool := worker.NewWorkerPool(cfg, logger)
+	pool.SetLedger(processed)
+	pool.SetQuarantine(openQuarantine(cfg))
+
+	// Registered first so it runs after the pool has stopped
+	defer writeRunReport(pool, cfg)
 
-	expectedFiles, err := discoverAndQueueFiles(ctx, pool, telemetryFolder, cfg, logger)
+	expectedFiles, err := discoverAndQueueFiles(ctx, pool, processed, directory, logger)
 	if err != nil {
 		logger.Error("File discovery failed",
 			zap.Error(err),
-			zap.String("path", telemetryFolder),
+			zap.Strings("paths", roots),
 			zap.String("action", "Check directory permissions and IBT files exist"))
 		return
 	}
 	log.Printf("STARTUP: Found %d IBT files to process", expectedFiles)
 
-	// Initialize progress display
-	if !*quiet {
+	// --display swaps the per-file progress bars for the live worker dashboard
+	switch {
+	case quiet:
+	case display:
+		stopDashboard := startDashboard(pool, cfg.WorkerCount, expectedFiles)
+		defer stopDashboard()
+	default:
 		progress = worker.NewProgressDisplay(cfg.WorkerCount, expectedFiles)
 		pool.SetProgressDisplay(progress)
 		progress.Start()
@@ -165,7 +165,7 @@ func Process(telemetryFolder string) {
 	}()
 
 	// Wait for completion
-	waitForCompletion(ctx, pool, startTime, expectedFiles, *quiet)
+	waitForCompletion(ctx, pool, startTime, expectedFiles, quiet)
 
 	// Write memory profile if MEM_PROFILE environment variable is set
 	if memProfile := os.Getenv("MEM_PROFILE"); memProfile != "" {
@@ -187,9 +187,65 @@ func Process(telemetryFolder string) {
 	}
 }
 
-func discoverAndQueueFiles(ctx context.Context, pool *worker.WorkerPool, telemetryFolder string, cfg *config.Config, logger *zap.Logger) (int, error) {
-	directory := processing.NewDir(telemetryFolder, cfg, logger)
-	files := directory.WatchDir()
+func newLogger(verbose bool) (*zap.Logger, error) {
+	if verbose {
+		// Verbose mode: full development logging
+		return zap.NewDevelopment()
+	}
+
+	// Silent mode: errors and above
+	config := zap.NewProductionConfig()
+	config.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
+	return config.Build()
+}
+
+// startHTTPServers serves /metrics and, with ENABLE_PPROF, the pprof
+// handlers. Both go on one server when METRICS_PORT and PPROF_PORT match.
+func startHTTPServers(ctx context.Context, cfg *config.Config) {
+	muxes := make(map[string]*http.ServeMux)
+	mux := func(port string) *http.ServeMux {
+		if muxes[port] == nil {
+			muxes[port] = http.NewServeMux()
+		}
+		return muxes[port]
+	}
+
+	if cfg.EnableMetrics {
+		metrics.Handle(mux(cfg.MetricsPort))
+	}
+	if cfg.EnablePprof {
+		metrics.HandlePprof(mux(cfg.PprofPort))
+	}
+
+	for port, m := range muxes {
+		if err := metrics.Serve(ctx, port, m, logger); err != nil {
+			logger.Error("HTTP server failed to start",
+				zap.Error(err),
+				zap.String("port", port),
+				zap.String("action", "Check port "+port+" is not in use or change METRICS_PORT / PPROF_PORT"))
+		}
+	}
+}
+
+// signalContext returns a context that is cancelled on SIGINT or SIGTERM.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalCh
+		cancel()
+	}()
+
+	return ctx, cancel
+}
+
+func discoverAndQueueFiles(ctx context.Context, pool *worker.WorkerPool, processed *ledger.Ledger, directory *processing.Directory, logger *zap.Logger) (int, error) {
+	files, err := directory.Scan()
+	if err != nil {
+		return 0, err
+	}
 
 	filesQueued := 0
 	for _, file := range files {
@@ -199,26 +255,70 @@ func discoverAndQueueFiles(ctx context.Context, pool *worker.WorkerPool, telemet
 		default:
 		}
 
-		fileName := file.Name()
-
-		if !strings.Contains(fileName, ".ibt") {
-			continue
+		queued, err := queueFile(pool, processed, file, logger)
+		if err != nil {
+			return filesQueued, err
 		}
 
-		workItem := worker.WorkItem{
-			FilePath:   filepath.Join(telemetryFolder, fileName),
-			FileInfo:   file,
-			RetryCount: 0,
+		if queued {
+			filesQueued++
 		}
+	}
 
-		if err := pool.SubmitFile(workItem); err != nil {
-			return filesQueued, err
-		}
+	return filesQueued, nil
+}
+
+// queueFile submits a single IBT file to the pool unless the ledger shows it
+// has already been sent. It reports whether the file was queued.
+func queueFile(pool *worker.WorkerPool, processed *ledger.Ledger, file processing.DiscoveredFile, logger *zap.Logger) (bool, error) {
+	fileName := file.Entry.Name()
+	filePath := file.Path
 
-		filesQueued++
+	if !processing.IsIBTFile(fileName) {
+		return false, nil
 	}
 
-	return filesQueued, nil
+	info, err := file.Entry.Info()
+	if err != nil {
+		logger.Warn("Could not get file info", zap.String("file", fileName), zap.Error(err))
+		return false, nil
+	}
+
+	// Archive members are extracted to a new path for every copy of the
+	// archive, so they are matched on their contents
+	isProcessed := processed.IsProcessed
+	if file.Archive != "" {
+		isProcessed = processed.IsProcessedContent
+	}
+
+	alreadySent, err := isProcessed(filePath, info)
+	if err != nil {
+		logger.Warn("Could not check ledger, file will be sent",
+			zap.String("file", fileName),
+			zap.Error(err))
+	}
+	if alreadySent {
+		logger.Debug("Skipping already processed file", zap.String("file", fileName))
+		return false, nil
+	}
+
+	if pool.IsQuarantined(filePath, info) {
+		logger.Debug("Skipping quarantined file", zap.String("file", fileName))
+		return false, nil
+	}
+
+	workItem := worker.WorkItem{
+		FilePath:   filePath,
+		FileInfo:   file.Entry,
+		RetryCount: 0,
+		Archive:    file.Archive,
+	}
+
+	if err := pool.SubmitFile(workItem); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func waitForCompletion(ctx context.Context, pool *worker.WorkerPool, startTime time.Time, expectedFiles int, quiet bool) {
@@ -231,10 +331,44 @@ func waitForCompletion(ctx context.Context, pool *worker.WorkerPool, startTime t
 			time.Sleep(20 * time.Millisecond)
 			metrics := pool.GetMetrics()
 
-			if metrics.QueueDepth == 0 && metrics.TotalFilesProcessed >= expectedFiles {
+			if metrics.QueueDepth == 0 && metrics.TotalFilesProcessed+metrics.TotalFilesFailed >= expectedFiles {
 				// Completion - metrics available via Prometheus, no log needed
 				return
 			}
 		}
 	}
 }
+
+// openQuarantine loads the quarantine list, carrying on without one if it
+// cannot be read
+func openQuarantine(cfg *config.Config) *ledger.Quarantine {
+	quarantine, err := ledger.OpenQuarantine(cfg.QuarantinePath)
+	if err != nil {
+		logger.Warn("Quarantine list unavailable, broken files will be tried on every run",
+			zap.Error(err),
+			zap.String("path", cfg.QuarantinePath))
+		return nil
+	}
+	return quarantine
+}
+
+// writeRunReport saves the summary of every file the pool finished and
+// prints the totals.
+func writeRunReport(pool *worker.WorkerPool, cfg *config.Config) {
+	run := pool.Report()
+
+	path, err := run.Write(cfg.ReportDirectory)
+	if err != nil {
+		logger.Error("Failed to write run report",
+			zap.Error(err),
+			zap.String("path", cfg.ReportDirectory))
+		path = "not saved"
+	}
+
+	log.Printf("REPORT: %d files ingested, %d partial, %d failed (%d quarantined), %d records in %d batches (%s) across %d sessions and %d laps in %s, %s",
+		run.Ingested, run.Partial, run.Failed, run.Quarantined, run.Records, run.Batches, formatBytes(run.Bytes), run.Sessions, run.Laps,
+		time.Duration(run.Duration*float64(time.Second)).Round(time.Second), path)
+	if run.PersistedBatches > 0 {
+		log.Printf("REPORT: %d batches are in the spool and have not reached the server yet", run.PersistedBatches)
+	}
+}
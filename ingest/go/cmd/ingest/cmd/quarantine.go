@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var quarantineCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "Inspect and manage IBT files that could not be processed",
+	Long: `Files that are not valid IBT files or cannot be decoded are added to the quarantine list
+	(INGEST_QUARANTINE_PATH) instead of being retried, and ingest and watch skip them until they change.
+	Release a file once it has been fixed to send it on the next run.`,
+}
+
+var quarantineLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List quarantined files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := openQuarantineList()
+		if err != nil {
+			return err
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"File", "Kind", "Attempts", "Quarantined", "Error"})
+
+		entries := q.Entries()
+		for _, entry := range entries {
+			t.AppendRow(table.Row{filepath.Base(entry.Path), entry.Kind, entry.Attempts, entry.QuarantinedAt.Format(time.RFC3339), entry.Error})
+		}
+
+		t.AppendFooter(table.Row{fmt.Sprintf("%d files", len(entries)), "", "", "", q.Path()})
+		t.Render()
+		return nil
+	},
+}
+
+var quarantineReleaseCmd = &cobra.Command{
+	Use:   "release <file.ibt>...",
+	Short: "Take files off the quarantine list so the next run tries them again",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := openQuarantineList()
+		if err != nil {
+			return err
+		}
+
+		for _, path := range args {
+			released, err := q.Release(path)
+			if err != nil {
+				return err
+			}
+			if released {
+				fmt.Printf("Released %s\n", path)
+			} else {
+				fmt.Printf("%s is not quarantined\n", path)
+			}
+		}
+		return nil
+	},
+}
+
+var quarantineClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Release every quarantined file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := openQuarantineList()
+		if err != nil {
+			return err
+		}
+
+		released := len(q.Entries())
+		if err := q.Reset(); err != nil {
+			return err
+		}
+		fmt.Printf("Released %d files from %s\n", released, q.Path())
+		return nil
+	},
+}
+
+func init() {
+	quarantineCmd.AddCommand(quarantineLsCmd, quarantineReleaseCmd, quarantineClearCmd)
+	rootCmd.AddCommand(quarantineCmd)
+}
+
+func openQuarantineList() (*ledger.Quarantine, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return ledger.OpenQuarantine(cfg.QuarantinePath)
+}
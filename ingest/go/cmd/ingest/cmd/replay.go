@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/replay"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replaySpeed   string
+	replayWindow  time.Duration
+	replayLoop    bool
+	replaySession string
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file.ibt|folder>",
+	Short: "Publish an IBT file as if it were being recorded live",
+	Long: `Send an IBT file's ticks to INGEST_SINK paced by SessionTime, in small time ordered batches
+	rather than the bulk batches ingest sends. Use it to drive the live dashboards without the sim
+	or to load test telemetryService with realistic traffic.
+
+	--speed takes a multiplier (1x, 4x, 0.5x) or max to send as fast as the sink accepts. Each batch
+	covers --batch of SessionTime. Tick times are moved onto the current time and the rows are written
+	under a new session ID, --session-id or <session>_replay_<start> for each pass, so a replay does not
+	overwrite the recorded session or an earlier --loop.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		speed, err := replay.ParseSpeed(replaySpeed)
+		if err != nil {
+			return err
+		}
+		if replayWindow <= 0 {
+			return fmt.Errorf("invalid --batch %s\nAction: Use a positive duration like 100ms", replayWindow)
+		}
+
+		files, err := ibtFiles(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		var pool *messaging.ConnectionPool
+		if (cfg.Sink == "" || cfg.Sink == sink.KindRabbitMQ) && !cfg.DisableRabbitMQ {
+			// A single connection is plenty for one paced stream
+			cfg.RabbitMQPoolSize = 1
+
+			pool, err = messaging.NewConnectionPool(cfg)
+			if err != nil {
+				return err
+			}
+			defer pool.Close()
+		}
+
+		fp, err := processing.NewFileProcessor(cfg, 0, pool)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var passStarted time.Time
+		fp.SetSinkFactory(func(sessionID, trackName string, sessionTime time.Time) (sink.Sink, error) {
+			replayID := replaySession
+			if replayID == "" {
+				replayID = fmt.Sprintf("%s_replay_%d", sessionID, passStarted.Unix())
+			}
+
+			out, err := sink.New(cfg, pool, replayID, sessionTime, 0)
+			if err != nil {
+				return nil, err
+			}
+			return replay.NewPacer(ctx, out, speed, replayWindow, replayID), nil
+		})
+
+		for {
+			passStarted = time.Now()
+			for _, file := range files {
+				started := time.Now()
+				result, err := fp.ProcessFile(ctx, filepath.Dir(file.path), file.entry)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return err
+				}
+
+				records, batches := 0, 0
+				if result.MessagingMetrics != nil {
+					records, batches = result.MessagingMetrics.TotalRecords, result.MessagingMetrics.TotalBatches
+				}
+				fmt.Printf("REPLAY: %s -> %d records in %d batches over %s (session %s, %s)\n",
+					file.entry.Name(), records, batches, time.Since(started).Round(time.Second), result.SessionID, result.TrackName)
+			}
+
+			if !replayLoop {
+				return nil
+			}
+		}
+	},
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replaySpeed, "speed", "1x", "playback speed: a multiplier like 1x or 4x, or max")
+	replayCmd.Flags().DurationVar(&replayWindow, "batch", 100*time.Millisecond, "SessionTime covered by each batch")
+	replayCmd.Flags().BoolVar(&replayLoop, "loop", false, "start again from the first file until interrupted")
+	replayCmd.Flags().StringVar(&replaySession, "session-id", "", "session ID to write the replay under, defaults to <session>_replay_<start> per pass")
+
+	rootCmd.AddCommand(replayCmd)
+}
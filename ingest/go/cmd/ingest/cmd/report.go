@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/report"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var reportJSON bool
+
+var reportCmd = &cobra.Command{
+	Use:   "report [file.ibt...]",
+	Short: "Show what the last run, or the named files, sent to the server",
+	Long: `Without arguments print the report of the most recent ingest or watch run from REPORT_DIR.
+	With IBT files print the report kept in the ledger for each of them, from the run that ingested it.
+
+	Persisted batches are waiting in the spool and have not reached the server yet.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			run, path, err := report.Latest(cfg.ReportDirectory)
+			if err != nil {
+				return err
+			}
+			if reportJSON {
+				return printJSON(run)
+			}
+
+			fmt.Printf("REPORT: %s\n", path)
+			fmt.Printf("Run %s for %s: %d files ingested, %d partial, %d failed, %d records in %d batches (%s), %d sessions, %d laps\n",
+				run.Started.Format(time.RFC3339), time.Duration(run.Duration*float64(time.Second)).Round(time.Second),
+				run.Ingested, run.Partial, run.Failed, run.Records, run.Batches, formatBytes(run.Bytes), run.Sessions, run.Laps)
+			printFileReports(run.Reports)
+			return nil
+		}
+
+		processed, err := ledger.Open(cfg.LedgerPath)
+		if err != nil {
+			return err
+		}
+
+		reports := make([]report.File, 0, len(args))
+		for _, path := range args {
+			entry, ok := processed.Get(path)
+			switch {
+			case !ok:
+				return fmt.Errorf("%s is not in the ledger %s\nAction: Only files ingested successfully are recorded, check the run report for failures", path, cfg.LedgerPath)
+			case entry.Report == nil:
+				// Ingested before reports were kept
+				reports = append(reports, report.File{Path: entry.Path, Status: report.StatusIngested, Started: entry.ProcessedAt})
+			default:
+				reports = append(reports, *entry.Report)
+			}
+		}
+
+		if reportJSON {
+			return printJSON(reports)
+		}
+		printFileReports(reports)
+		return nil
+	},
+}
+
+func init() {
+	reportCmd.Flags().BoolVar(&reportJSON, "json", false, "print the report as JSON")
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printFileReports(reports []report.File) {
+	if len(reports) == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"File", "Status", "Sessions", "Laps", "Records", "Batches", "Sent", "Bytes", "Failed", "Spooled", "Duration", "Error"})
+	for _, r := range reports {
+		t.AppendRow(table.Row{
+			filepath.Base(r.Path),
+			r.Status,
+			len(r.Sessions),
+			r.Laps,
+			r.Records,
+			r.Batches,
+			r.SentBatches,
+			formatBytes(r.Bytes),
+			r.FailedBatches,
+			r.PersistedBatches,
+			time.Duration(r.Duration * float64(time.Second)).Round(time.Millisecond),
+			r.Error,
+		})
+	}
+	t.Render()
+}
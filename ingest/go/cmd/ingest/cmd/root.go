@@ -1,15 +1,20 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
 	"github.com/spf13/cobra"
 )
 
 var (
-	telemetryPath string
-	display       bool
+	telemetryPaths []string
+	display        bool
+
+	configPath      string
+	configOverrides []string
+	quiet           bool
+	verbose         bool
 )
 
 var rootCmd = &cobra.Command{
@@ -21,16 +26,7 @@ var rootCmd = &cobra.Command{
 	The ingest service uploads all the sessions that are stored on your local machine to the IRacing dashboard service. It can be run in the background or as a one off.`,
 	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Inside rootCmd Run with args: %v \n", cmd)
-
-		telemetryPath := cmd.Flag("telemetryPath").Value.String()
-
-		if telemetryPath == "" {
-			fmt.Println("no telemetry path found")
-		} else {
-			Process(telemetryPath)
-		}
-
+		Process(append(telemetryPaths, args...))
 	},
 }
 
@@ -44,6 +40,18 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.Flags().BoolVarP(&display, "display", "d", false, "terminal display of the ingest process")
-	rootCmd.Flags().StringVarP(&telemetryPath, "telemetryPath", "p", "", "path to IRacing telemetry folder")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "config file, defaults to INGEST_CONFIG or "+config.DefaultConfigPath())
+	rootCmd.PersistentFlags().StringArrayVar(&configOverrides, "set", nil, "override a config key, e.g. --set batch_size_records=8000 (repeatable)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "disable the progress display")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
+
+	rootCmd.Flags().BoolVarP(&display, "display", "d", false, "live worker dashboard instead of the per-file progress bars")
+	rootCmd.Flags().StringSliceVarP(&telemetryPaths, "telemetryPath", "p", nil, "path to IRacing telemetry folder (repeatable)")
+	rootCmd.Flags().BoolVarP(&fresh, "fresh", "f", false, "will clean the local store of files that have been processed and start from fresh")
+	addFilterFlags(rootCmd)
+}
+
+// loadConfig resolves the config file, environment and --set overrides
+func loadConfig() (*config.Config, error) {
+	return config.Load(configPath, configOverrides)
 }
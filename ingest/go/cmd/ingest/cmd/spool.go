@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/spool"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var spoolCmd = &cobra.Command{
+	Use:   "spool",
+	Short: "Inspect and manage batches persisted after RabbitMQ failures",
+	Long: `When a batch cannot be published it is written to the disk spool (SPOOL_DIR) instead of being dropped.
+	The spool is replayed automatically once the broker is reachable again, these commands manage it by hand.`,
+}
+
+var spoolLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List spooled batches",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openSpool()
+		if err != nil {
+			return err
+		}
+
+		entries, err := s.List()
+		if err != nil {
+			return err
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"Batch", "Size", "Queued"})
+
+		var total int64
+		for _, entry := range entries {
+			t.AppendRow(table.Row{entry.BatchID, formatBytes(entry.Size), entry.QueuedAt.Format(time.RFC3339)})
+			total += entry.Size
+		}
+
+		t.AppendFooter(table.Row{fmt.Sprintf("%d batches", len(entries)), formatBytes(total), s.Dir()})
+		t.Render()
+		return nil
+	},
+}
+
+var spoolReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Publish spooled batches to RabbitMQ",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		s, err := spool.New(cfg.SpoolDirectory, cfg.SpoolMaxBytes)
+		if err != nil {
+			return err
+		}
+
+		// A single connection is plenty for a one off replay
+		cfg.RabbitMQPoolSize = 1
+
+		pool, err := messaging.NewConnectionPool(cfg)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+		pool.SetSpool(s)
+
+		sent, err := pool.ReplaySpool()
+		fmt.Printf("Replayed %d batches\n", sent)
+		return err
+	},
+}
+
+var spoolPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete every spooled batch without sending it",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := openSpool()
+		if err != nil {
+			return err
+		}
+
+		removed, err := s.Purge()
+		fmt.Printf("Removed %d batches from %s\n", removed, s.Dir())
+		return err
+	},
+}
+
+func init() {
+	spoolCmd.AddCommand(spoolLsCmd, spoolReplayCmd, spoolPurgeCmd)
+	rootCmd.AddCommand(spoolCmd)
+}
+
+func openSpool() (*spool.Spool, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return spool.New(cfg.SpoolDirectory, cfg.SpoolMaxBytes)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/validate"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateJSON   bool
+	validateStrict bool
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <file.ibt|folder>",
+	Short: "Check IBT files for bad data without sending anything",
+	Long: `Decode IBT files the same way ingest does and report data-quality problems instead of publishing:
+	NaN and Inf values, the 0xFFFFFFFF sentinel telemetryService stores as 0, SessionTime gaps and reversals,
+	GPS dropouts while moving, impossible LapDistPct jumps within a lap and truncated final records.
+
+	Exits non-zero when any file has errors, or warnings too with --strict. Run with --json for a
+	report scripts can consume.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, err := ibtFiles(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		fp, err := processing.NewFileProcessor(cfg, 0, nil)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		reports := make([]*validate.Report, 0, len(files))
+		for _, file := range files {
+			report, err := validateFile(ctx, fp, file)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+
+		if validateJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				return err
+			}
+		} else {
+			for _, report := range reports {
+				printReport(report)
+			}
+		}
+
+		failed := 0
+		for _, report := range reports {
+			if report.Errors > 0 || (validateStrict && report.Warnings > 0) {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d files failed validation\nAction: Fix or remove the files above before ingesting them", failed, len(files))
+		}
+		return nil
+	},
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "print the reports as JSON")
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "fail on warnings as well as errors")
+
+	rootCmd.AddCommand(validateCmd)
+}
+
+// validateFile runs one file through ProcessFile with a Checker per group.
+// Files that cannot be read are reported rather than stopping the run.
+func validateFile(ctx context.Context, fp *processing.FileProcessor, file ibtFile) (*validate.Report, error) {
+	info, err := fp.Inspect(file.path)
+	if err != nil {
+		report := validate.NewReport(file.entry.Name(), "", 0)
+		report.Add(validate.Issue{Kind: validate.KindReadError, Detail: err.Error()})
+		return report, nil
+	}
+
+	expected := 0
+	for _, group := range info.Groups {
+		expected += group.Records
+	}
+	report := validate.NewReport(info.File, info.SessionID, expected)
+
+	// Groups reach the factory in the order Inspect lists them
+	next := 0
+	fp.SetSinkFactory(func(sessionID, trackName string, sessionTime time.Time) (sink.Sink, error) {
+		group, records := next, 0
+		if next < len(info.Groups) {
+			group, records = info.Groups[next].Index, info.Groups[next].Records
+		}
+		next++
+		return validate.NewChecker(report, group, records), nil
+	})
+
+	if _, err := fp.ProcessFile(ctx, filepath.Dir(file.path), file.entry); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		report.Add(validate.Issue{Kind: validate.KindReadError, Detail: err.Error()})
+	}
+
+	report.Sort()
+	return report, nil
+}
+
+func printReport(report *validate.Report) {
+	fmt.Printf("VALIDATE: %s (session %s): %d/%d records, %d errors, %d warnings\n",
+		report.File, report.SessionID, report.Ticks, report.Expected, report.Errors, report.Warnings)
+
+	if len(report.Issues) == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Severity", "Kind", "Field", "Group", "Session", "Lap", "Start", "End", "Ticks", "Detail"})
+	for _, issue := range report.Issues {
+		t.AppendRow(table.Row{
+			issue.Severity,
+			issue.Kind,
+			issue.Field,
+			issue.Group,
+			issue.Session,
+			issue.Lap,
+			fmt.Sprintf("%.3f", issue.Start),
+			fmt.Sprintf("%.3f", issue.End),
+			issue.Ticks,
+			issue.Detail,
+		})
+	}
+	t.Render()
+	fmt.Println()
+}
@@ -1 +1,122 @@
 package cmd
+
+import (
+	"log"
+	"runtime"
+	"strings"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/worker"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep watching the telemetry folder and ingest new sessions as they are written",
+	Long: `Run ingest as a long lived daemon. The telemetry folder is rescanned every WATCH_INTERVAL
+	and any IBT file that has not been written to for FILE_AGE_THRESHOLD is sent to the worker pool.
+
+	Files already in the processed ledger are skipped, run with --fresh to send everything again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		Watch(append(telemetryPaths, args...))
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringSliceVarP(&telemetryPaths, "telemetryPath", "p", nil, "path to IRacing telemetry folder (repeatable)")
+	watchCmd.Flags().BoolVarP(&fresh, "fresh", "f", false, "will clean the local store of files that have been processed and start from fresh")
+	addFilterFlags(watchCmd)
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+func Watch(telemetryFolders []string) {
+	var err error
+	logger, err = newLogger(verbose)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		logger.Fatal("Invalid configuration", zap.Error(err))
+	}
+
+	// Workers live for the whole watch session rather than WORKER_TIMEOUT
+	cfg.WorkerTimeout = 0
+
+	if cfg.GoMaxProcs > 0 {
+		runtime.GOMAXPROCS(cfg.GoMaxProcs)
+	}
+
+	roots, err := telemetryRoots(telemetryFolders, cfg)
+	if err != nil {
+		logger.Fatal("Invalid telemetry directories", zap.Error(err))
+	}
+
+	directory := processing.NewDir(roots, cfg, logger)
+	if err := selectFiles(directory, cfg); err != nil {
+		logger.Fatal("Invalid file filter", zap.Error(err))
+	}
+
+	processed, err := ledger.Open(cfg.LedgerPath)
+	if err != nil {
+		logger.Fatal("Failed to open processed file ledger",
+			zap.Error(err),
+			zap.String("path", cfg.LedgerPath))
+	}
+
+	if fresh {
+		if err := processed.Reset(); err != nil {
+			logger.Fatal("Failed to clear processed file ledger",
+				zap.Error(err),
+				zap.String("path", cfg.LedgerPath))
+		}
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	startHTTPServers(ctx, cfg)
+
+	pool := worker.NewWorkerPool(cfg, logger)
+	pool.SetLedger(processed)
+	pool.SetQuarantine(openQuarantine(cfg))
+
+	// Files that fail are picked up again on the next scan
+	pool.SetUnsentHandler(directory.Forget)
+	defer writeRunReport(pool, cfg)
+
+	if err := pool.Start(); err != nil {
+		logger.Fatal("Failed to start worker pool",
+			zap.Error(err),
+			zap.String("action", "Check system resources and configuration"))
+	}
+	defer func() {
+		if err := pool.Stop(); err != nil {
+			logger.Error("Error stopping worker pool",
+				zap.Error(err))
+		}
+	}()
+
+	log.Printf("WATCH: Watching %s for new IBT files every %v", strings.Join(roots, ", "), cfg.WatchInterval)
+
+	for file := range directory.Watch(ctx, cfg.WatchInterval) {
+		queued, err := queueFile(pool, processed, file, logger)
+		if err != nil {
+			logger.Error("Failed to queue file",
+				zap.String("file", file.Path),
+				zap.Error(err))
+			break
+		}
+
+		if queued {
+			log.Printf("WATCH: Queued %s", file.Entry.Name())
+		}
+	}
+
+	log.Printf("WATCH: Stopping, waiting for in-flight files to finish")
+}
@@ -0,0 +1,62 @@
+package channels
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// validName matches IBT variable names, which double as the suffix of the
+// QuestDB column so nothing outside this set is allowed through.
+var validName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// ColumnPrefix keeps manifest channels clear of the fixed TelemetryTicks
+// columns, QuestDB column names are case-insensitive. It is part of the
+// table schema and must equal persistance.ChannelColumnPrefix in
+// telemetryService, which writes the same columns.
+const ColumnPrefix = "ch_"
+
+// Load reads a channel manifest: one IBT variable name per line, blank
+// lines and anything after a # are ignored. Duplicates are dropped.
+func Load(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel manifest %s: %w\nAction: Check CHANNEL_MANIFEST points to an existing file", path, err)
+	}
+	defer f.Close()
+
+	var names []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+
+		if !validName.MatchString(name) {
+			return nil, fmt.Errorf("invalid channel %q on line %d of %s\nAction: Use the IBT variable name exactly, e.g. LFrideHeight", name, lineNum, path)
+		}
+
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read channel manifest %s: %w", path, err)
+	}
+
+	return names, nil
+}
+
+// Column returns the QuestDB column a manifest channel is stored in.
+func Column(name string) string {
+	return ColumnPrefix + name
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func origin(cfg *Config, key string) string {
+	for _, setting := range cfg.Settings() {
+		if setting.Key == key {
+			return setting.Origin
+		}
+	}
+	return ""
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	testCases := []struct {
+		name       string
+		file       string
+		env        string
+		overrides  []string
+		want       int
+		wantOrigin string
+	}{
+		{"Default", "", "", nil, 3, OriginDefault},
+		{"File", "max_retries: 5", "", nil, 5, OriginFile},
+		{"FileUpperCase", "MAX_RETRIES: 5", "", nil, 5, OriginFile},
+		{"EnvOverFile", "max_retries: 5", "7", nil, 7, OriginEnv},
+		{"FlagOverEnv", "max_retries: 5", "7", []string{"max-retries=9"}, 9, OriginFlag},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != "" {
+				t.Setenv("MAX_RETRIES", tc.env)
+			} else {
+				unsetEnv(t, "MAX_RETRIES")
+			}
+
+			cfg, err := Load(writeConfig(t, tc.file), tc.overrides)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.MaxRetries != tc.want {
+				t.Errorf("MaxRetries = %d, want %d", cfg.MaxRetries, tc.want)
+			}
+			if got := origin(cfg, "MAX_RETRIES"); got != tc.wantOrigin {
+				t.Errorf("MAX_RETRIES origin = %s, want %s", got, tc.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestLoadValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		file      string
+		overrides []string
+		wantErr   string
+	}{
+		{"Valid", "worker_count: 4", nil, ""},
+		{"UnknownFileKey", "worker_cuont: 4", nil, "unknown key worker_cuont in the config file"},
+		{"UnknownFlag", "", []string{"WORKER_CUONT=4"}, "unknown key WORKER_CUONT in --set"},
+		{"BadInteger", "worker_count: four", nil, `WORKER_COUNT="four" (file) is not a valid integer`},
+		{"NegativeRetries", "", []string{"MAX_RETRIES=-1"}, "MAX_RETRIES=-1 cannot be negative"},
+		{"RetryDelays", "retry_delay: 2m\nretry_max_delay: 1m", nil, "RETRY_MAX_DELAY=1m0s cannot be less than RETRY_DELAY=2m0s"},
+		{"BadTimezone", "ingest_timezone: Mars/Olympus", nil, "INGEST_TIMEZONE"},
+		{"ArchiveCap", "", []string{"ARCHIVE_MAX_BYTES=0"}, "ARCHIVE_MAX_BYTES=0 must be greater than zero"},
+		{"BadOverride", "", []string{"WORKER_COUNT"}, "invalid --set"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"WORKER_COUNT", "MAX_RETRIES", "RETRY_DELAY", "RETRY_MAX_DELAY", "INGEST_TIMEZONE", "ARCHIVE_MAX_BYTES"} {
+				unsetEnv(t, key)
+			}
+
+			_, err := Load(writeConfig(t, tc.file), tc.overrides)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Load error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// unsetEnv clears key for the test and restores it afterwards
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mirrors the sink package constants, which import this package
+var (
+	validSinks       = []string{"rabbitmq", "questdb", "file", "stdout"}
+	validFileFormats = []string{"json", "protobuf"}
+)
+
+// validate catches values that parse but cannot work
+func (c *Config) validate(s *source) {
+	positive := func(key string, value int) {
+		if value < 1 {
+			s.errs = append(s.errs, fmt.Sprintf("%s=%d must be at least 1", key, value))
+		}
+	}
+
+	positive("WORKER_COUNT", c.WorkerCount)
+	positive("FILE_QUEUE_SIZE", c.FileQueueSize)
+	positive("BATCH_SIZE_BYTES", c.BatchSizeBytes)
+	positive("BATCH_SIZE_RECORDS", c.BatchSizeRecords)
+	positive("RABBITMQ_POOL_SIZE", c.RabbitMQPoolSize)
+	positive("RABBITMQ_BATCH_SIZE", c.RabbitMQBatchSize)
+	positive("CAR_IDX_STRIDE", c.CarIdxStride)
+	positive("ADAPTIVE_MIN_BATCH_RECORDS", c.AdaptiveMinBatchRecords)
+	positive("CIRCUIT_BREAKER_THRESHOLD", c.CircuitBreakerThreshold)
+
+	if c.MaxRetries < 0 {
+		s.errs = append(s.errs, fmt.Sprintf("MAX_RETRIES=%d cannot be negative", c.MaxRetries))
+	}
+	if c.AdaptiveHeapLimit < 0 {
+		s.errs = append(s.errs, fmt.Sprintf("ADAPTIVE_HEAP_LIMIT=%d cannot be negative", c.AdaptiveHeapLimit))
+	}
+	if c.SpoolMaxBytes < 0 {
+		s.errs = append(s.errs, fmt.Sprintf("SPOOL_MAX_BYTES=%d cannot be negative", c.SpoolMaxBytes))
+	}
+	if c.ArchiveMaxBytes <= 0 {
+		s.errs = append(s.errs, fmt.Sprintf("ARCHIVE_MAX_BYTES=%d must be greater than zero", c.ArchiveMaxBytes))
+	}
+
+	if c.WatchInterval <= 0 {
+		s.errs = append(s.errs, fmt.Sprintf("WATCH_INTERVAL=%s must be greater than zero", c.WatchInterval))
+	}
+	if c.RetryMaxDelay < c.RetryDelay {
+		s.errs = append(s.errs, fmt.Sprintf("RETRY_MAX_DELAY=%s cannot be less than RETRY_DELAY=%s", c.RetryMaxDelay, c.RetryDelay))
+	}
+	if c.CircuitBreakerCooldown <= 0 {
+		s.errs = append(s.errs, fmt.Sprintf("CIRCUIT_BREAKER_COOLDOWN=%s must be greater than zero", c.CircuitBreakerCooldown))
+	}
+	if c.FileProcessTimeout <= 0 {
+		s.errs = append(s.errs, fmt.Sprintf("FILE_PROCESS_TIMEOUT=%s must be greater than zero", c.FileProcessTimeout))
+	}
+
+	if !slices.Contains(validSinks, c.Sink) {
+		s.errs = append(s.errs, fmt.Sprintf("INGEST_SINK=%q must be one of %s", c.Sink, strings.Join(validSinks, ", ")))
+	}
+	if !slices.Contains(validFileFormats, c.SinkFileFormat) {
+		s.errs = append(s.errs, fmt.Sprintf("SINK_FILE_FORMAT=%q must be one of %s", c.SinkFileFormat, strings.Join(validFileFormats, ", ")))
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil || c.Timezone == "" {
+		s.errs = append(s.errs, fmt.Sprintf("INGEST_TIMEZONE=%q must be Local, UTC or an IANA zone like Europe/London", c.Timezone))
+	}
+
+	validPort := func(key, value string) {
+		if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+			s.errs = append(s.errs, fmt.Sprintf("%s=%q is not a valid port", key, value))
+		}
+	}
+	validPort("PPROF_PORT", c.PprofPort)
+	validPort("METRICS_PORT", c.MetricsPort)
+
+	if c.Sink == "rabbitmq" && !c.DisableRabbitMQ {
+		if u, err := url.Parse(c.RabbitMQURL); err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") {
+			s.errs = append(s.errs, "RABBITMQ_URL must be an amqp:// or amqps:// URL")
+		}
+	}
+}
+
+// Settings lists every key with the value in effect, secrets redacted
+func (c *Config) Settings() []Setting {
+	settings := make([]Setting, len(c.settings))
+	for i, setting := range c.settings {
+		setting.Value = redact(setting.Key, setting.Value)
+		settings[i] = setting
+	}
+	return settings
+}
+
+func redact(key, value string) string {
+	if value == "" {
+		return value
+	}
+
+	if strings.HasSuffix(key, "_URL") {
+		if u, err := url.Parse(value); err == nil {
+			return u.Redacted()
+		}
+	}
+
+	for _, secret := range []string{"TOKEN", "SECRET", "PASSWORD", "ACCESS_KEY"} {
+		if strings.Contains(key, secret) {
+			return "xxxxx"
+		}
+	}
+	return value
+}
@@ -2,19 +2,24 @@ package dashboard
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/jedib0t/go-pretty/v6/progress"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
 )
 
 type dashboard struct {
-	pw      progress.Writer
+	out     io.Writer
 	metrics map[int]*WorkerMetrics
+	summary Summary
 	mu      sync.RWMutex
 	done    chan struct{}
+	stopped sync.Once
 }
 
 type WorkerMetrics struct {
@@ -43,34 +48,41 @@ type WorkerMetrics struct {
 	QueueSize     int
 	QueueCapacity int
 	QueueUsage    int // Percentage 0-100
+	BatchSize     int // Records per batch, as tuned by the publisher
 
 	// Status
 	Status           string // "Processing", "Idle", "Error", etc.
 	CircuitBreaker   bool
+	BreakerState     string // closed, open or half-open
 	FailedBatches    int
 	PersistedBatches int
+	Errors           int
 
 	// Timing
 	StartTime  time.Time
 	LastUpdate time.Time
 }
 
-func NewDashboard(workerCount int) *dashboard {
-	pw := progress.NewWriter()
-	pw.SetAutoStop(false)
-	pw.SetTrackerLength(20)
-	pw.SetUpdateFrequency(300 * time.Millisecond)
-	pw.Style().Visibility.TrackerOverall = true
+// Summary is the run wide line above the worker table
+type Summary struct {
+	FilesProcessed int
+	FilesExpected  int
+	QueueDepth     int
+	Errors         int
+	BreakerTrips   int
+	StartTime      time.Time
+}
 
+func NewDashboard(workerCount int) *dashboard {
 	return &dashboard{
-		pw:      pw,
-		metrics: make(map[int]*WorkerMetrics),
+		out:     os.Stdout,
+		metrics: make(map[int]*WorkerMetrics, workerCount),
+		summary: Summary{StartTime: time.Now()},
 		done:    make(chan struct{}),
 	}
 }
 
 func (d *dashboard) Start() {
-	go d.pw.Render()
 	go d.renderStats()
 }
 
@@ -93,47 +105,114 @@ func (d *dashboard) printTable() {
 	defer d.mu.RUnlock()
 
 	// Clear screen and move cursor to top
-	fmt.Print("\033[2J\033[H")
+	fmt.Fprint(d.out, "\033[2J\033[H")
+
+	s := d.summary
+	elapsed := time.Since(s.StartTime).Round(time.Second)
+	fmt.Fprintf(d.out, "Files %d/%d | Queued %d | Errors %d | Breaker trips %d | Elapsed %v\n\n",
+		s.FilesProcessed, s.FilesExpected, s.QueueDepth, s.Errors, s.BreakerTrips, elapsed)
 
 	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Worker", "Files", "Records/s", "MB/s", "RabbitMQ Queue"})
+	t.SetOutputMirror(d.out)
+	t.AppendHeader(table.Row{"Worker", "Status", "File", "Files", "Records", "Records/s", "MB/s", "Publish Queue", "Batch", "Failed", "Persisted", "Breaker"})
+
+	ids := make([]int, 0, len(d.metrics))
+	for id := range d.metrics {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
 
 	totalRecords := 0
+	totalRate := 0
 	totalThroughput := 0.0
+	totalFailed := 0
+	totalPersisted := 0
+
+	for _, id := range ids {
+		m := d.metrics[id]
 
-	for id, m := range d.metrics {
 		queuePct := 0.0
 		if m.QueueCapacity > 0 {
 			queuePct = float64(m.QueueSize) / float64(m.QueueCapacity) * 100
 		}
 
+		breaker := "closed"
+		switch {
+		case m.BreakerState == "half-open":
+			breaker = text.FgYellow.Sprint("half-open")
+		case m.CircuitBreaker:
+			breaker = text.FgRed.Sprint("OPEN")
+		}
+
 		t.AppendRow(table.Row{
 			fmt.Sprintf("Worker %d", id),
+			statusColor(m.Status),
+			truncate(filepath.Base(m.CurrentFile), 32),
 			m.FilesProcessed,
+			m.TotalRecords,
 			m.RecordsPerSec,
 			fmt.Sprintf("%.2f", m.ThroughputMBps),
 			fmt.Sprintf("%d/%d (%.0f%%)", m.QueueSize, m.QueueCapacity, queuePct),
+			m.BatchSize,
+			m.FailedBatches,
+			m.PersistedBatches,
+			breaker,
 		})
 
-		totalRecords += m.RecordsPerSec
+		totalRecords += m.TotalRecords
+		totalRate += m.RecordsPerSec
 		totalThroughput += m.ThroughputMBps
+		totalFailed += m.FailedBatches
+		totalPersisted += m.PersistedBatches
 	}
 
 	t.AppendSeparator()
-	t.AppendFooter(table.Row{"Total", "-", totalRecords, fmt.Sprintf("%.2f", totalThroughput), "-"})
+	t.AppendFooter(table.Row{"Total", "", "", s.FilesProcessed, totalRecords, totalRate, fmt.Sprintf("%.2f", totalThroughput), "", "", totalFailed, totalPersisted, ""})
 
 	t.SetStyle(table.StyleColoredBright)
 	t.Render()
 }
 
+func statusColor(status string) string {
+	switch status {
+	case "PROCESSING":
+		return text.FgGreen.Sprint(status)
+	case "ERROR":
+		return text.FgRed.Sprint(status)
+	}
+	return status
+}
+
+func truncate(s string, n int) string {
+	if s == "." || s == "" {
+		return ""
+	}
+	if len(s) > n {
+		return "..." + s[len(s)-n+3:]
+	}
+	return s
+}
+
 func (d *dashboard) UpdateWorker(id int, metrics *WorkerMetrics) {
 	d.mu.Lock()
 	d.metrics[id] = metrics
 	d.mu.Unlock()
 }
 
+// UpdateSummary replaces the run wide totals
+func (d *dashboard) UpdateSummary(summary Summary) {
+	d.mu.Lock()
+	if summary.StartTime.IsZero() {
+		summary.StartTime = d.summary.StartTime
+	}
+	d.summary = summary
+	d.mu.Unlock()
+}
+
+// Stop draws the final state once and stops refreshing
 func (d *dashboard) Stop() {
-	close(d.done)
-	d.pw.Stop()
+	d.stopped.Do(func() {
+		close(d.done)
+		d.printTable()
+	})
 }
@@ -0,0 +1,355 @@
+package export
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Supported values for --format
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+	FormatNDJSON  = "ndjson"
+)
+
+// keyColumns lead every export so rows can be told apart without the
+// channels that were picked.
+var keyColumns = []string{"session_id", "session_num", "session_type", "lap_id", "tick_time"}
+
+// Options selects what ends up in the exported files
+type Options struct {
+	Dir    string
+	Format string
+
+	// Session numbers, types or names to keep, empty keeps every session
+	Sessions []string
+
+	// Laps to keep, empty keeps every lap
+	Laps map[int]bool
+}
+
+type valueKind int
+
+const (
+	kindNumber valueKind = iota
+	kindString
+	kindTime
+)
+
+// Column reads one output value from a Telemetry record, either a proto
+// field or a manifest channel from the Channels map.
+type Column struct {
+	name  string
+	kind  valueKind
+	field protoreflect.FieldDescriptor
+}
+
+// Columns resolves the requested names against the Telemetry message. Names
+// that are not Telemetry fields are treated as IBT channels and returned in
+// extra so the caller can have them decoded. With nothing requested every
+// numeric Telemetry field is exported.
+func Columns(names []string) (columns []Column, extra []string, err error) {
+	fields := (&messaging.Telemetry{}).ProtoReflect().Descriptor().Fields()
+
+	seen := make(map[string]bool)
+	add := func(name string) error {
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil {
+			if !validChannel.MatchString(name) {
+				return fmt.Errorf("unknown channel %q\nAction: Use a Telemetry field such as speed or an IBT variable name such as LFrideHeight", name)
+			}
+			columns = append(columns, Column{name: name, kind: kindNumber})
+			extra = append(extra, name)
+			return nil
+		}
+
+		switch fd.Kind() {
+		case protoreflect.DoubleKind, protoreflect.FloatKind, protoreflect.Uint32Kind, protoreflect.Int32Kind, protoreflect.Uint64Kind, protoreflect.Int64Kind:
+			columns = append(columns, Column{name: name, kind: kindNumber, field: fd})
+		case protoreflect.StringKind:
+			columns = append(columns, Column{name: name, kind: kindString, field: fd})
+		case protoreflect.MessageKind:
+			if fd.Message().FullName() != "google.protobuf.Timestamp" {
+				return fmt.Errorf("channel %q cannot be exported\nAction: Pick a numeric or text field", name)
+			}
+			columns = append(columns, Column{name: name, kind: kindTime, field: fd})
+		default:
+			return fmt.Errorf("channel %q cannot be exported\nAction: Pick a numeric or text field", name)
+		}
+		return nil
+	}
+
+	for _, name := range keyColumns {
+		if err := add(name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(names) == 0 {
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			if fd.Kind() == protoreflect.DoubleKind || fd.Kind() == protoreflect.Uint32Kind {
+				seen[string(fd.Name())] = true
+				columns = append(columns, Column{name: string(fd.Name()), kind: kindNumber, field: fd})
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := add(strings.TrimSpace(name)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return columns, extra, nil
+}
+
+var validChannel = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// value returns nil when a channel is missing or not a finite number
+func (c Column) value(t *messaging.Telemetry) any {
+	if c.field == nil {
+		v, ok := t.Channels[c.name]
+		if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return v
+	}
+
+	v := t.ProtoReflect().Get(c.field)
+	switch c.kind {
+	case kindString:
+		return v.String()
+	case kindTime:
+		return t.GetTickTime().AsTime()
+	}
+
+	var f float64
+	switch c.field.Kind() {
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		f = v.Float()
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		f = float64(v.Uint())
+	default:
+		f = float64(v.Int())
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return f
+}
+
+// rowWriter is one output format
+type rowWriter interface {
+	Write(row []any) error
+	Close() error
+}
+
+// Sink writes one session group to <Dir>/<SubSessionID>_<track>.<format>.
+// The file is only created once a tick passes the session and lap filters.
+type Sink struct {
+	opts    Options
+	columns []Column
+	path    string
+
+	f       *os.File
+	w       rowWriter
+	metrics messaging.PublishMetrics
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9-]+`)
+
+// Validate checks the options before any file is read
+func (o Options) Validate() error {
+	switch o.Format {
+	case FormatCSV, FormatParquet, FormatNDJSON:
+	default:
+		return fmt.Errorf("unknown export format %q\nAction: Use one of csv, parquet or ndjson", o.Format)
+	}
+	if o.Dir == "" {
+		return fmt.Errorf("no export folder set\nAction: Pass a folder with --out")
+	}
+	return nil
+}
+
+// New prepares the export of one session group
+func New(opts Options, columns []Column, sessionID, trackName string) (*Sink, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	track := strings.Trim(unsafeName.ReplaceAllString(trackName, "_"), "_")
+	if track == "" {
+		track = "unknown"
+	}
+
+	return &Sink{
+		opts:    opts,
+		columns: columns,
+		path:    filepath.Join(opts.Dir, fmt.Sprintf("%s_%s.%s", sessionID, track, opts.Format)),
+	}, nil
+}
+
+func (s *Sink) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
+	records, err := messaging.TransformStructBatch(ticks, channels)
+	if err != nil {
+		return err
+	}
+
+	row := make([]any, len(s.columns))
+	written := 0
+
+	for _, record := range records {
+		if !s.keep(record) {
+			continue
+		}
+
+		if s.w == nil {
+			if err := s.open(); err != nil {
+				return err
+			}
+		}
+
+		for i, col := range s.columns {
+			row[i] = col.value(record)
+		}
+		if err := s.w.Write(row); err != nil {
+			return fmt.Errorf("failed to write %s: %w\nAction: Check disk space and file permissions", s.path, err)
+		}
+		written++
+	}
+
+	if written > 0 {
+		s.metrics.TotalBatches++
+		s.metrics.SentBatches++
+		s.metrics.TotalRecords += written
+	}
+	return nil
+}
+
+func (s *Sink) keep(t *messaging.Telemetry) bool {
+	if len(s.opts.Laps) > 0 {
+		lap, err := strconv.Atoi(t.LapId)
+		if err != nil || !s.opts.Laps[lap] {
+			return false
+		}
+	}
+
+	if len(s.opts.Sessions) == 0 {
+		return true
+	}
+	for _, session := range s.opts.Sessions {
+		if session == t.SessionNum || strings.EqualFold(session, t.SessionType) || strings.EqualFold(session, t.SessionName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Sink) open() error {
+	if err := os.MkdirAll(s.opts.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory %s: %w\nAction: Check --out points to a writable location", s.opts.Dir, err)
+	}
+
+	f, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w\nAction: Check disk space and file permissions", s.path, err)
+	}
+
+	var w rowWriter
+	switch s.opts.Format {
+	case FormatCSV:
+		w, err = newCSVWriter(f, s.columns)
+	case FormatParquet:
+		w, err = newParquetWriter(f, s.columns)
+	default:
+		w, err = newNDJSONWriter(f, s.columns)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	s.f, s.w = f, w
+	return nil
+}
+
+// Per-car records and session metadata are not part of the export
+func (s *Sink) ExecCars(cars []*messaging.CarTelemetry) error     { return nil }
+func (s *Sink) ExecSession(meta *messaging.SessionMetadata) error { return nil }
+
+func (s *Sink) Close() error {
+	if s.w == nil {
+		return nil
+	}
+
+	err := s.w.Close()
+	if closeErr := s.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to finish %s: %w\nAction: Check disk space, the file is likely incomplete", s.path, err)
+	}
+
+	log.Printf("EXPORT: Wrote %d rows to %s", s.metrics.TotalRecords, s.path)
+	s.w = nil
+	return nil
+}
+
+func (s *Sink) GetMetrics() messaging.PublishMetrics {
+	return s.metrics
+}
+
+// Path is the file the group is written to
+func (s *Sink) Path() string {
+	return s.path
+}
+
+// ParseLaps reads a lap list such as "3,5-7"
+func ParseLaps(spec string) (map[int]bool, error) {
+	laps := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid lap %q: %w\nAction: List laps as numbers or ranges, e.g. 3,5-7", part, err)
+		}
+
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || last < first {
+				return nil, fmt.Errorf("invalid lap range %q\nAction: List laps as numbers or ranges, e.g. 3,5-7", part)
+			}
+		}
+
+		for lap := first; lap <= last; lap++ {
+			laps[lap] = true
+		}
+	}
+	return laps, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
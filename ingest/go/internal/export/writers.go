@@ -0,0 +1,154 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// csvWriter leaves missing channels as empty cells
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(out io.Writer, columns []Column) (*csvWriter, error) {
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+
+	w := csv.NewWriter(out)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: w, record: make([]string, len(columns))}, nil
+}
+
+func (c *csvWriter) Write(row []any) error {
+	for i, value := range row {
+		switch v := value.(type) {
+		case nil:
+			c.record[i] = ""
+		case float64:
+			c.record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			c.record[i] = v
+		case time.Time:
+			c.record[i] = formatTime(v)
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonWriter writes one JSON object per tick, missing channels are null
+type ndjsonWriter struct {
+	buf     *bufio.Writer
+	enc     *json.Encoder
+	columns []Column
+	record  map[string]any
+}
+
+func newNDJSONWriter(out io.Writer, columns []Column) (*ndjsonWriter, error) {
+	buf := bufio.NewWriter(out)
+	return &ndjsonWriter{
+		buf:     buf,
+		enc:     json.NewEncoder(buf),
+		columns: columns,
+		record:  make(map[string]any, len(columns)),
+	}, nil
+}
+
+func (n *ndjsonWriter) Write(row []any) error {
+	for i, value := range row {
+		if t, ok := value.(time.Time); ok {
+			value = formatTime(t)
+		}
+		n.record[n.columns[i].name] = value
+	}
+	return n.enc.Encode(n.record)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.buf.Flush()
+}
+
+// parquetWriter stores every column as optional so missing channels are
+// nulls. Rows are buffered by the writer and flushed as row groups.
+type parquetWriter struct {
+	w       *parquet.Writer
+	indexes []int
+	row     parquet.Row
+}
+
+func newParquetWriter(out io.Writer, columns []Column) (*parquetWriter, error) {
+	group := make(parquet.Group, len(columns))
+	for _, col := range columns {
+		switch col.kind {
+		case kindString:
+			group[col.name] = parquet.Optional(parquet.String())
+		case kindTime:
+			group[col.name] = parquet.Optional(parquet.Timestamp(parquet.Nanosecond))
+		default:
+			group[col.name] = parquet.Optional(parquet.Leaf(parquet.DoubleType))
+		}
+	}
+	schema := parquet.NewSchema("telemetry", group)
+
+	// The schema orders columns by name, rows have to follow it
+	position := make(map[string]int, len(columns))
+	for i, path := range schema.Columns() {
+		position[path[0]] = i
+	}
+
+	indexes := make([]int, len(columns))
+	for i, col := range columns {
+		index, ok := position[col.name]
+		if !ok {
+			return nil, fmt.Errorf("column %s missing from parquet schema", col.name)
+		}
+		indexes[i] = index
+	}
+
+	return &parquetWriter{
+		w:       parquet.NewWriter(out, schema),
+		indexes: indexes,
+		row:     make(parquet.Row, len(columns)),
+	}, nil
+}
+
+func (p *parquetWriter) Write(row []any) error {
+	for i, value := range row {
+		var v parquet.Value
+		switch val := value.(type) {
+		case nil:
+			p.row[p.indexes[i]] = parquet.NullValue().Level(0, 0, p.indexes[i])
+			continue
+		case float64:
+			v = parquet.DoubleValue(val)
+		case string:
+			v = parquet.ByteArrayValue([]byte(val))
+		case time.Time:
+			v = parquet.Int64Value(val.UnixNano())
+		}
+		p.row[p.indexes[i]] = v.Level(0, 1, p.indexes[i])
+	}
+
+	_, err := p.w.WriteRows([]parquet.Row{p.row})
+	return err
+}
+
+func (p *parquetWriter) Close() error {
+	return p.w.Close()
+}
@@ -0,0 +1,267 @@
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/report"
+)
+
+// Entry records a single IBT file that has been fully ingested.
+type Entry struct {
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"mod_time"`
+	Hash        string    `json:"hash"`
+	ProcessedAt time.Time `json:"processed_at"`
+
+	// What was sent, missing for files recorded before reports existed
+	Report *report.File `json:"report,omitempty"`
+}
+
+// Ledger is a small on-disk store of the files already sent, so repeated
+// runs over the same telemetry folder only pick up new or changed files.
+type Ledger struct {
+	path    string
+	entries map[string]Entry
+	mu      sync.Mutex
+	saveMu  sync.Mutex
+}
+
+// Open loads the ledger at path, starting empty if it does not exist yet.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{
+		path:    path,
+		entries: make(map[string]Entry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger %s: %w\nAction: Check the file permissions or run with --fresh", path, err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger %s: %w\nAction: The ledger is corrupted - run with --fresh to rebuild it", path, err)
+	}
+
+	for _, entry := range entries {
+		l.entries[entry.Path] = entry
+	}
+
+	return l, nil
+}
+
+// Reset forgets every processed file and removes the ledger from disk.
+func (l *Ledger) Reset() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = make(map[string]Entry)
+
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove ledger %s: %w\nAction: Check the file permissions", l.path, err)
+	}
+	return nil
+}
+
+// IsProcessed reports whether the file has already been ingested unchanged.
+// Size and mtime are checked first; the content hash is only computed when
+// the mtime moved but the size did not, e.g. after a copy or touch.
+func (l *Ledger) IsProcessed(path string, info os.FileInfo) (bool, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.Lock()
+	entry, exists := l.entries[key]
+	l.mu.Unlock()
+
+	if !exists || entry.Size != info.Size() {
+		return false, nil
+	}
+
+	if entry.ModTime.Equal(info.ModTime()) {
+		return true, nil
+	}
+
+	hash, err := HashFile(path)
+	if err != nil {
+		return false, err
+	}
+	if hash != entry.Hash {
+		return false, nil
+	}
+
+	entry.ModTime = info.ModTime()
+	l.mu.Lock()
+	l.entries[key] = entry
+	l.mu.Unlock()
+
+	return true, l.save()
+}
+
+// IsProcessedContent is IsProcessed, but also true when a file with the same
+// contents was ingested under another path. Archive members are checked this
+// way as their extracted path changes with each copy of the archive.
+func (l *Ledger) IsProcessedContent(path string, info os.FileInfo) (bool, error) {
+	if processed, err := l.IsProcessed(path, info); processed || err != nil {
+		return processed, err
+	}
+
+	// Only hash when some entry could match
+	if !l.hasSize(info.Size()) {
+		return false, nil
+	}
+
+	hash, err := HashFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if entry.Size == info.Size() && entry.Hash == hash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *Ledger) hasSize(size int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range l.entries {
+		if entry.Size == size {
+			return true
+		}
+	}
+	return false
+}
+
+// Record marks the file as ingested and persists the ledger along with
+// its report, which may be nil.
+func (l *Ledger) Record(path string, info os.FileInfo, rep *report.File) error {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	hash, err := HashFile(path)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.entries[key] = Entry{
+		Path:        key,
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		Hash:        hash,
+		ProcessedAt: time.Now(),
+		Report:      rep,
+	}
+	l.mu.Unlock()
+
+	return l.save()
+}
+
+// Get returns the entry for path
+func (l *Ledger) Get(path string) (Entry, bool) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return Entry{}, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.entries[key]
+	return entry, ok
+}
+
+// Entries returns every entry, oldest first
+func (l *Ledger) Entries() []Entry {
+	l.mu.Lock()
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	l.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ProcessedAt.Before(entries[j].ProcessedAt)
+	})
+	return entries
+}
+
+// Len returns the number of files in the ledger.
+func (l *Ledger) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// save writes the ledger to a temp file and renames it into place so a
+// crash mid-write never leaves a truncated ledger behind.
+func (l *Ledger) save() error {
+	l.saveMu.Lock()
+	defer l.saveMu.Unlock()
+
+	l.mu.Lock()
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	l.mu.Unlock()
+
+	return writeJSON(l.path, entries, "INGEST_LEDGER_PATH")
+}
+
+// writeJSON replaces path with v, env names the setting for the path
+func writeJSON(path string, v any, env string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w\nAction: Check %s points to a writable location", path, err, env)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w\nAction: Check disk space and file permissions", tmp, err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// HashFile returns the hex encoded SHA-256 of the file contents.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s for hashing: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, contents string, modTime time.Time) os.FileInfo {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set mtime on %s: %v", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", path, err)
+	}
+	return info
+}
+
+func TestLedgerIsProcessed(t *testing.T) {
+	recorded := time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		contents string
+		modTime  time.Time
+		want     bool
+	}{
+		{"Unchanged", "telemetry", recorded, true},
+		{"Touched", "telemetry", recorded.Add(time.Hour), true},
+		{"SameSizeNewContents", "telemetrx", recorded.Add(time.Hour), false},
+		{"Grown", "telemetry and more", recorded, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "session.ibt")
+
+			l, err := Open(filepath.Join(dir, "ledger.json"))
+			if err != nil {
+				t.Fatalf("Failed to open ledger: %v", err)
+			}
+			if err := l.Record(path, writeFile(t, path, "telemetry", recorded), nil); err != nil {
+				t.Fatalf("Failed to record file: %v", err)
+			}
+
+			got, err := l.IsProcessed(path, writeFile(t, path, tc.contents, tc.modTime))
+			if err != nil {
+				t.Fatalf("IsProcessed failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("IsProcessed = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLedgerIsProcessedContent(t *testing.T) {
+	recorded := time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		contents string
+		want     bool
+	}{
+		{"SameContents", "telemetry", true},
+		{"SameSizeOtherContents", "telemetrx", false},
+		{"OtherSize", "other telemetry", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			original := filepath.Join(dir, "session.ibt")
+			member := filepath.Join(dir, "archive", "session.ibt")
+			if err := os.MkdirAll(filepath.Dir(member), 0o755); err != nil {
+				t.Fatalf("Failed to create archive folder: %v", err)
+			}
+
+			l, err := Open(filepath.Join(dir, "ledger.json"))
+			if err != nil {
+				t.Fatalf("Failed to open ledger: %v", err)
+			}
+			if err := l.Record(original, writeFile(t, original, "telemetry", recorded), nil); err != nil {
+				t.Fatalf("Failed to record file: %v", err)
+			}
+
+			got, err := l.IsProcessedContent(member, writeFile(t, member, tc.contents, recorded))
+			if err != nil {
+				t.Fatalf("IsProcessedContent failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("IsProcessedContent = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLedgerReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.ibt")
+	ledgerPath := filepath.Join(dir, "ledger.json")
+	info := writeFile(t, path, "telemetry", time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC))
+
+	l, err := Open(ledgerPath)
+	if err != nil {
+		t.Fatalf("Failed to open ledger: %v", err)
+	}
+	if err := l.Record(path, info, nil); err != nil {
+		t.Fatalf("Failed to record file: %v", err)
+	}
+
+	reopened, err := Open(ledgerPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen ledger: %v", err)
+	}
+	if reopened.Len() != 1 {
+		t.Fatalf("Reopened ledger has %d entries, want 1", reopened.Len())
+	}
+	if processed, _ := reopened.IsProcessed(path, info); !processed {
+		t.Errorf("File recorded before reopening is not processed")
+	}
+
+	if err := reopened.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if _, err := os.Stat(ledgerPath); !os.IsNotExist(err) {
+		t.Errorf("Ledger file still exists after Reset")
+	}
+}
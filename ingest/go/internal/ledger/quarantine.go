@@ -0,0 +1,152 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QuarantineEntry is a file that failed in a way retrying cannot fix
+type QuarantineEntry struct {
+	Path          string    `json:"path"`
+	Size          int64     `json:"size"`
+	ModTime       time.Time `json:"mod_time"`
+	Kind          string    `json:"kind"`
+	Error         string    `json:"error"`
+	Attempts      int       `json:"attempts"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// Quarantine lists broken files so later runs skip them rather than
+// failing on them again. A file that changes on disk gets another try.
+type Quarantine struct {
+	path    string
+	entries map[string]QuarantineEntry
+	mu      sync.Mutex
+	saveMu  sync.Mutex
+}
+
+// OpenQuarantine loads the list at path, starting empty if it does not exist yet.
+func OpenQuarantine(path string) (*Quarantine, error) {
+	q := &Quarantine{
+		path:    path,
+		entries: make(map[string]QuarantineEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine %s: %w\nAction: Check the file permissions", path, err)
+	}
+
+	var entries []QuarantineEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse quarantine %s: %w\nAction: The list is corrupted - run ingest quarantine clear", path, err)
+	}
+
+	for _, entry := range entries {
+		q.entries[entry.Path] = entry
+	}
+	return q, nil
+}
+
+// Add quarantines the file as it is now
+func (q *Quarantine) Add(path string, info os.FileInfo, kind, reason string, attempts int) error {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	q.entries[key] = QuarantineEntry{
+		Path:          key,
+		Size:          info.Size(),
+		ModTime:       info.ModTime(),
+		Kind:          kind,
+		Error:         reason,
+		Attempts:      attempts,
+		QuarantinedAt: time.Now(),
+	}
+	q.mu.Unlock()
+
+	return q.save()
+}
+
+// Contains reports whether the file is quarantined and unchanged since
+func (q *Quarantine) Contains(path string, info os.FileInfo) bool {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, ok := q.entries[key]
+	return ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime())
+}
+
+// Release takes the file off the list, reporting whether it was on it
+func (q *Quarantine) Release(path string) (bool, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+
+	q.mu.Lock()
+	_, ok := q.entries[key]
+	delete(q.entries, key)
+	q.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+	return true, q.save()
+}
+
+// Reset releases every file and removes the list from disk
+func (q *Quarantine) Reset() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = make(map[string]QuarantineEntry)
+
+	if err := os.Remove(q.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove quarantine %s: %w\nAction: Check the file permissions", q.path, err)
+	}
+	return nil
+}
+
+// Entries returns every quarantined file, oldest first
+func (q *Quarantine) Entries() []QuarantineEntry {
+	q.mu.Lock()
+	entries := make([]QuarantineEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		entries = append(entries, entry)
+	}
+	q.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].QuarantinedAt.Before(entries[j].QuarantinedAt)
+	})
+	return entries
+}
+
+func (q *Quarantine) Path() string {
+	return q.path
+}
+
+func (q *Quarantine) save() error {
+	q.saveMu.Lock()
+	defer q.saveMu.Unlock()
+
+	entries := q.Entries()
+	return writeJSON(q.path, entries, "INGEST_QUARANTINE_PATH")
+}
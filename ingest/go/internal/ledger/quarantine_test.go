@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuarantineContains(t *testing.T) {
+	modTime := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		contents string
+		modTime  time.Time
+		want     bool
+	}{
+		{"Unchanged", "broken", modTime, true},
+		{"Touched", "broken", modTime.Add(time.Minute), false},
+		{"Rewritten", "fixed file", modTime, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "session.ibt")
+
+			q, err := OpenQuarantine(filepath.Join(dir, "quarantine.json"))
+			if err != nil {
+				t.Fatalf("Failed to open quarantine: %v", err)
+			}
+			info := writeFile(t, path, "broken", modTime)
+			if err := q.Add(path, info, "parse", "bad header", 1); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+
+			info = writeFile(t, path, tc.contents, tc.modTime)
+			if got := q.Contains(path, info); got != tc.want {
+				t.Errorf("Contains = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuarantineReopenAndRelease(t *testing.T) {
+	dir := t.TempDir()
+	listPath := filepath.Join(dir, "quarantine.json")
+	path := filepath.Join(dir, "session.ibt")
+	info := writeFile(t, path, "broken", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	q, err := OpenQuarantine(listPath)
+	if err != nil {
+		t.Fatalf("Failed to open quarantine: %v", err)
+	}
+	if err := q.Add(path, info, "bad_file", "not an IBT file", 3); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	reopened, err := OpenQuarantine(listPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen quarantine: %v", err)
+	}
+	if !reopened.Contains(path, info) {
+		t.Fatalf("reopened quarantine lost %s", path)
+	}
+	entries := reopened.Entries()
+	if len(entries) != 1 || entries[0].Kind != "bad_file" || entries[0].Attempts != 3 {
+		t.Errorf("Entries = %+v, want the bad_file entry with 3 attempts", entries)
+	}
+
+	released, err := reopened.Release(path)
+	if err != nil || !released {
+		t.Fatalf("Release = %v, %v, want true", released, err)
+	}
+	if released, _ := reopened.Release(path); released {
+		t.Errorf("second Release reported the file was still listed")
+	}
+
+	again, err := OpenQuarantine(listPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen quarantine: %v", err)
+	}
+	if again.Contains(path, info) {
+		t.Errorf("released file is still quarantined after reopening")
+	}
+}
@@ -0,0 +1,166 @@
+package messaging
+
+import (
+	"math"
+	"runtime/debug"
+	rtmetrics "runtime/metrics"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/metrics"
+)
+
+const (
+	// How often the tuner reacts, so one slow publish does not halve the batch
+	tuneInterval = time.Second
+
+	// Heap use above this share of the limit counts as memory pressure
+	heapPressureRatio = 0.8
+
+	// Publish queue fill that counts as a backlog, and that is low enough to grow again
+	backlogQueueRatio = 0.75
+	idleQueueRatio    = 0.25
+
+	// The flush interval never stretches past this many BATCH_TIMEOUTs
+	maxIntervalFactor = 8
+)
+
+type pressure int
+
+const (
+	noPressure pressure = iota
+	memoryPressure
+	backlogPressure
+)
+
+// batchTuner sizes a worker's batches from how well publishing keeps up.
+// BATCH_SIZE_RECORDS and BATCH_SIZE_BYTES are the ceiling. The batch is
+// halved when the heap nears its limit or when the publish queue backs up or
+// publishes get slow, then grown back a tenth at a time once it is healthy.
+// A backlog also stretches the flush interval so fewer batches are queued.
+//
+// One tuner is shared by every PubSub a worker opens, so what it learnt on
+// the last file carries over to the next one.
+type batchTuner struct {
+	mu sync.Mutex
+
+	enabled       bool
+	minRecords    int
+	maxRecords    int
+	maxBytes      int
+	baseInterval  time.Duration
+	targetLatency time.Duration
+	heapLimit     uint64
+	worker        string
+
+	records    int
+	interval   time.Duration
+	latency    time.Duration // Moving average of successful publishes
+	lastAdjust time.Time
+}
+
+func newBatchTuner(cfg *config.Config, workerID int) *batchTuner {
+	t := &batchTuner{
+		enabled:       cfg.MemoryTuning,
+		minRecords:    min(cfg.AdaptiveMinBatchRecords, cfg.BatchSizeRecords),
+		maxRecords:    cfg.BatchSizeRecords,
+		maxBytes:      cfg.BatchSizeBytes,
+		baseInterval:  cfg.BatchTimeout,
+		targetLatency: cfg.AdaptiveTargetLatency,
+		heapLimit:     uint64(cfg.AdaptiveHeapLimit),
+		worker:        strconv.Itoa(workerID),
+		records:       cfg.BatchSizeRecords,
+		interval:      cfg.BatchTimeout,
+	}
+
+	// A lower GOMEMLIMIT is the one the runtime will actually enforce
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 && (t.heapLimit == 0 || uint64(limit) < t.heapLimit) {
+		t.heapLimit = uint64(limit)
+	}
+
+	return t
+}
+
+// observeLatency records how long a successful publish took
+func (t *batchTuner) observeLatency(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.latency == 0 {
+		t.latency = d
+		return
+	}
+	t.latency = (t.latency*4 + d) / 5
+}
+
+// adjust is called on every flush with the publish queue fill and returns
+// the pressure it reacted to, if any.
+func (t *batchTuner) adjust(queued, capacity int) pressure {
+	if !t.enabled {
+		return noPressure
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Since(t.lastAdjust) < tuneInterval {
+		return noPressure
+	}
+	t.lastAdjust = time.Now()
+
+	fill := 0.0
+	if capacity > 0 {
+		fill = float64(queued) / float64(capacity)
+	}
+
+	var seen pressure
+	switch {
+	case t.heapLimit > 0 && float64(heapInUse()) > float64(t.heapLimit)*heapPressureRatio:
+		seen = memoryPressure
+		t.records = max(t.records/2, t.minRecords)
+		metrics.PressureEventsTotal.WithLabelValues("memory").Inc()
+
+	case fill >= backlogQueueRatio || (t.targetLatency > 0 && t.latency > t.targetLatency):
+		seen = backlogPressure
+		t.records = max(t.records/2, t.minRecords)
+		t.interval = min(t.interval*2, t.baseInterval*maxIntervalFactor)
+		metrics.PressureEventsTotal.WithLabelValues("backlog").Inc()
+
+	case fill <= idleQueueRatio && (t.targetLatency == 0 || t.latency <= t.targetLatency/2):
+		t.records = min(t.records+max(t.maxRecords/10, 1), t.maxRecords)
+		t.interval = max(t.interval/2, t.baseInterval)
+	}
+
+	metrics.AdaptiveBatchRecords.WithLabelValues(t.worker).Set(float64(t.records))
+	return seen
+}
+
+// limits returns the batch size in records and bytes and the flush interval
+// to use until the next adjustment. The byte limit shrinks with the records.
+func (t *batchTuner) limits() (records, bytes int, interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	bytes = t.maxBytes
+	if t.maxRecords > 0 && t.records < t.maxRecords {
+		bytes = int(int64(t.maxBytes) * int64(t.records) / int64(t.maxRecords))
+	}
+	return t.records, bytes, t.interval
+}
+
+var heapSample = []rtmetrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+var heapSampleMu sync.Mutex
+
+// heapInUse reads live heap bytes without stopping the world like ReadMemStats
+func heapInUse() uint64 {
+	heapSampleMu.Lock()
+	defer heapSampleMu.Unlock()
+
+	rtmetrics.Read(heapSample)
+	if heapSample[0].Value.Kind() != rtmetrics.KindUint64 {
+		return 0
+	}
+	return heapSample[0].Value.Uint64()
+}
@@ -0,0 +1,89 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTuner(enabled bool) *batchTuner {
+	return &batchTuner{
+		enabled:       enabled,
+		minRecords:    1000,
+		maxRecords:    8000,
+		maxBytes:      8 * 1024 * 1024,
+		baseInterval:  50 * time.Millisecond,
+		targetLatency: 100 * time.Millisecond,
+		worker:        "0",
+		records:       8000,
+		interval:      50 * time.Millisecond,
+	}
+}
+
+func TestBatchTunerAdjust(t *testing.T) {
+	testCases := []struct {
+		name         string
+		enabled      bool
+		records      int
+		interval     time.Duration
+		latency      time.Duration
+		queued       int
+		want         pressure
+		wantRecords  int
+		wantInterval time.Duration
+	}{
+		{"Disabled", false, 8000, 50 * time.Millisecond, time.Second, 100, noPressure, 8000, 50 * time.Millisecond},
+		{"QueueBacklog", true, 8000, 50 * time.Millisecond, 0, 80, backlogPressure, 4000, 100 * time.Millisecond},
+		{"SlowPublishes", true, 8000, 50 * time.Millisecond, 200 * time.Millisecond, 0, backlogPressure, 4000, 100 * time.Millisecond},
+		{"FloorAtMin", true, 1500, 50 * time.Millisecond, 0, 100, backlogPressure, 1000, 100 * time.Millisecond},
+		{"IntervalCapped", true, 8000, 400 * time.Millisecond, 0, 100, backlogPressure, 4000, 400 * time.Millisecond},
+		{"IdleGrows", true, 4000, 200 * time.Millisecond, 10 * time.Millisecond, 0, noPressure, 4800, 100 * time.Millisecond},
+		{"GrowthCappedAtMax", true, 7500, 50 * time.Millisecond, 0, 0, noPressure, 8000, 50 * time.Millisecond},
+		{"Steady", true, 4000, 50 * time.Millisecond, 80 * time.Millisecond, 50, noPressure, 4000, 50 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tuner := newTestTuner(tc.enabled)
+			tuner.records, tuner.interval, tuner.latency = tc.records, tc.interval, tc.latency
+
+			if got := tuner.adjust(tc.queued, 100); got != tc.want {
+				t.Errorf("adjust = %v, want %v", got, tc.want)
+			}
+
+			records, _, interval := tuner.limits()
+			if records != tc.wantRecords {
+				t.Errorf("records = %d, want %d", records, tc.wantRecords)
+			}
+			if interval != tc.wantInterval {
+				t.Errorf("interval = %v, want %v", interval, tc.wantInterval)
+			}
+		})
+	}
+}
+
+func TestBatchTunerAdjustOncePerInterval(t *testing.T) {
+	tuner := newTestTuner(true)
+
+	if got := tuner.adjust(100, 100); got != backlogPressure {
+		t.Fatalf("first adjust = %v, want backlog", got)
+	}
+	if got := tuner.adjust(100, 100); got != noPressure {
+		t.Errorf("second adjust within tuneInterval = %v, want none", got)
+	}
+	if records, _, _ := tuner.limits(); records != 4000 {
+		t.Errorf("records = %d, want 4000 after one halving", records)
+	}
+}
+
+func TestBatchTunerLimitsScaleBytes(t *testing.T) {
+	tuner := newTestTuner(true)
+	tuner.records = 2000
+
+	records, bytes, _ := tuner.limits()
+	if records != 2000 {
+		t.Errorf("records = %d, want 2000", records)
+	}
+	if want := 2 * 1024 * 1024; bytes != want {
+		t.Errorf("bytes = %d, want %d", bytes, want)
+	}
+}
@@ -0,0 +1,118 @@
+package messaging
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// circuitBreaker stops publishers hammering a broker that is down. It is
+// shared by every PubSub on a ConnectionPool. After threshold publishes in a
+// row fail it opens and batches go straight to the spool. Once cooldown has
+// passed a single publish is let through as a probe: success closes the
+// breaker, failure opens it for another cooldown.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	threshold int
+	cooldown  time.Duration
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// allow reports whether a publish may be attempted. When it returns true for
+// a half-open breaker the caller is the probe and must report the outcome.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		log.Printf("RabbitMQ circuit breaker half-open, probing the broker")
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// success closes the breaker and reports whether it had been tripped.
+func (b *circuitBreaker) success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	recovered := b.state != breakerClosed || b.failures > 0
+	if b.state != breakerClosed {
+		log.Printf("RabbitMQ circuit breaker closed, broker is accepting batches again")
+	}
+
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+	return recovered
+}
+
+// failure records a failed publish and reports whether it opened the breaker.
+func (b *circuitBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+
+	switch {
+	case b.state == breakerHalfOpen:
+		b.probing = false
+	case b.state == breakerClosed && b.failures >= b.threshold:
+	default:
+		return false
+	}
+
+	b.state = breakerOpen
+	b.openedAt = time.Now()
+	log.Printf("RabbitMQ circuit breaker open after %d consecutive failures, spooling batches for %v",
+		b.failures, b.cooldown)
+	return true
+}
+
+func (b *circuitBreaker) snapshot() (breakerState, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.failures
+}
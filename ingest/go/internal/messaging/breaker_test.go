@@ -0,0 +1,78 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	// Steps run in order against one breaker: f fails a publish, s succeeds
+	// one, a asks to publish, w waits out the cooldown
+	testCases := []struct {
+		name      string
+		steps     string
+		wantState breakerState
+		wantAllow bool
+	}{
+		{"ClosedAllows", "", breakerClosed, true},
+		{"BelowThreshold", "ff", breakerClosed, true},
+		{"SuccessResetsCount", "ffsff", breakerClosed, true},
+		{"OpensAtThreshold", "fff", breakerOpen, false},
+		{"StaysOpenInCooldown", "fffa", breakerOpen, false},
+		{"HalfOpenAfterCooldown", "fffw", breakerOpen, true},
+		{"OneProbeAtATime", "fffwa", breakerHalfOpen, false},
+		{"ProbeSuccessCloses", "fffwas", breakerClosed, true},
+		{"ProbeFailureReopens", "fffwaf", breakerOpen, false},
+	}
+
+	const cooldown = 20 * time.Millisecond
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newCircuitBreaker(3, cooldown)
+
+			for _, step := range tc.steps {
+				switch step {
+				case 'f':
+					b.failure()
+				case 's':
+					b.success()
+				case 'a':
+					b.allow()
+				case 'w':
+					time.Sleep(cooldown + 5*time.Millisecond)
+				}
+			}
+
+			if state, _ := b.snapshot(); state != tc.wantState {
+				t.Errorf("state = %s, want %s", state, tc.wantState)
+			}
+			if got := b.allow(); got != tc.wantAllow {
+				t.Errorf("allow = %v, want %v", got, tc.wantAllow)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerReports(t *testing.T) {
+	b := newCircuitBreaker(2, time.Hour)
+
+	if b.failure() {
+		t.Errorf("first failure reported the breaker opening")
+	}
+	if !b.failure() {
+		t.Errorf("failure at the threshold did not report the breaker opening")
+	}
+	if b.failure() {
+		t.Errorf("failure on an open breaker reported it opening again")
+	}
+	if _, failures := b.snapshot(); failures != 3 {
+		t.Errorf("failures = %d, want 3", failures)
+	}
+	if !b.success() {
+		t.Errorf("success after tripping did not report a recovery")
+	}
+	if b.success() {
+		t.Errorf("success on a healthy breaker reported a recovery")
+	}
+}
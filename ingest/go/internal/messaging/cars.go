@@ -0,0 +1,37 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ExecCars publishes one batch of per-car records. They are sent as they
+// arrive, the processor already batches them alongside the player ticks.
+func (ps *PubSub) ExecCars(cars []*CarTelemetry) error {
+	if len(cars) == 0 {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	batch := &CarTelemetryBatch{
+		Records:   cars,
+		BatchId:   ps.batchID(CarBatchPrefix, ps.totalCarRecords, len(cars), ps.totalCarBatches),
+		SessionId: ps.sessionID,
+		WorkerId:  uint32(ps.workerID),
+		Timestamp: timestamppb.New(time.Now()),
+	}
+
+	data, err := proto.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal car batch: %w\nAction: This is an internal error - check telemetry data validity", err)
+	}
+
+	ps.totalCarBatches++
+	ps.totalCarRecords += len(cars)
+	return ps.publish(batch, data)
+}
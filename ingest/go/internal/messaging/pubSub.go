@@ -2,6 +2,7 @@ package messaging
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/metrics"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/spool"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -19,10 +22,36 @@ import (
 type ConnectionPool struct {
 	connections []*amqp.Connection
 	channels    []*amqp.Channel
+	slotsMu     sync.RWMutex // Guards connections/channels while a slot is swapped
 	url         string
 	poolSize    int
 	current     atomic.Uint32 // Lock-free round-robin counter
 	closing     atomic.Bool
+	done        chan struct{}
+
+	// Connection settings reused when redialling
+	amqpConfig amqp.Config
+	watchWg    sync.WaitGroup
+	reconnects atomic.Int64
+
+	// Delivery guarantees
+	confirms       bool
+	persistent     bool
+	confirmTimeout time.Duration
+
+	// Disk spool for batches that could not be published
+	spool     *spool.Spool
+	replaying atomic.Bool
+	replayMu  sync.Mutex
+	replayWg  sync.WaitGroup
+
+	// Shared by all publishers so a dead broker is only probed by one
+	breaker *circuitBreaker
+
+	// Batch size tuning per worker, kept across files
+	config   *config.Config
+	tunersMu sync.Mutex
+	tuners   map[int]*batchTuner
 }
 
 var (
@@ -30,44 +59,223 @@ var (
 	publisherShutdown atomic.Bool
 )
 
-func NewConnectionPool(url string, poolSize int) (*ConnectionPool, error) {
-	pool := &ConnectionPool{
+// ErrNacked is returned when the broker negatively acknowledges a batch in confirm mode.
+var ErrNacked = errors.New("batch was nacked by RabbitMQ")
+
+const (
+	reconnectBaseDelay = 500 * time.Millisecond
+	reconnectMaxDelay  = 30 * time.Second
+)
+
+func NewConnectionPool(cfg *config.Config) (*ConnectionPool, error) {
+	pool := newConnectionPool(cfg)
+
+	for i := 0; i < pool.poolSize; i++ {
+		conn, ch, err := pool.dial(i)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		pool.watchWg.Add(1)
+		go pool.watchSlot(i, conn, ch)
+	}
+
+	return pool, nil
+}
+
+// OpenConnectionPool is NewConnectionPool for ingest and watch: slots that
+// cannot connect keep redialling in the background, and batches go to the
+// spool until the broker is up instead of failing the run.
+func OpenConnectionPool(cfg *config.Config, s *spool.Spool) *ConnectionPool {
+	pool := newConnectionPool(cfg)
+	pool.spool = s
+
+	for i := 0; i < pool.poolSize; i++ {
+		conn, ch, err := pool.dial(i)
+		if err != nil {
+			log.Printf("RabbitMQ connection %d unavailable, retrying in the background: %v", i, err)
+		}
+
+		pool.watchWg.Add(1)
+		go pool.watchSlot(i, conn, ch)
+	}
+
+	return pool
+}
+
+func newConnectionPool(cfg *config.Config) *ConnectionPool {
+	url := cfg.RabbitMQURL
+	poolSize := cfg.RabbitMQPoolSize
+
+	return &ConnectionPool{
 		connections: make([]*amqp.Connection, poolSize),
 		channels:    make([]*amqp.Channel, poolSize),
 		url:         url,
 		poolSize:    poolSize,
+		done:        make(chan struct{}),
+		amqpConfig: amqp.Config{
+			Heartbeat:  cfg.RabbitMQHeartbeat,
+			ChannelMax: uint16(cfg.RabbitMQChannelMax),
+			FrameSize:  cfg.RabbitMQFrameSize,
+			Locale:     "en_US",
+		},
+		confirms:       cfg.RabbitMQConfirms,
+		persistent:     cfg.RabbitMQPersistent,
+		confirmTimeout: cfg.RabbitMQConfirmTimeout,
+		breaker:        newCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown),
+		config:         cfg,
+		tuners:         make(map[int]*batchTuner),
 	}
+}
 
-	for i := 0; i < poolSize; i++ {
-		conn, err := amqp.Dial(url)
-		if err != nil {
-			pool.Close()
-			return nil, fmt.Errorf("failed to create RabbitMQ connection %d to %s: %w\nAction: Verify RabbitMQ is running and credentials are correct", i, url, err)
+// dial connects a slot for the first time
+func (p *ConnectionPool) dial(slot int) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.DialConfig(p.url, p.amqpConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create RabbitMQ connection %d to %s: %w\nAction: Verify RabbitMQ is running and credentials are correct", slot, p.url, err)
+	}
+
+	ch, err := p.openChannel(conn, slot)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	p.slotsMu.Lock()
+	p.connections[slot] = conn
+	p.channels[slot] = ch
+	p.slotsMu.Unlock()
+	return conn, ch, nil
+}
+
+// openChannel opens and configures a publishing channel on conn.
+func (p *ConnectionPool) openChannel(conn *amqp.Connection, slot int) (*amqp.Channel, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RabbitMQ channel %d: %w\nAction: Check RabbitMQ channel limits and service health", slot, err)
+	}
+
+	err = ch.Qos(1000, 0, false)
+	if err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to set QoS for channel %d: %w\nAction: Check RabbitMQ configuration allows prefetch settings", slot, err)
+	}
+
+	if p.confirms {
+		if err := ch.Confirm(false); err != nil {
+			ch.Close()
+			return nil, fmt.Errorf("failed to put channel %d into confirm mode: %w\nAction: Set RABBITMQ_CONFIRMS=false or check broker supports publisher confirms", slot, err)
+		}
+	}
+
+	return ch, nil
+}
+
+// watchSlot waits for the connection or channel in a slot to close and
+// replaces it, so a dropped connection heals without PubSub noticing. A slot
+// that never connected is dialled until it does.
+func (p *ConnectionPool) watchSlot(slot int, conn *amqp.Connection, ch *amqp.Channel) {
+	defer p.watchWg.Done()
+
+	if ch == nil {
+		conn, ch = p.reconnect(slot, conn)
+		if conn == nil {
+			return
 		}
+		log.Printf("Channel %d connected", slot)
+		p.ReplaySpoolAsync()
+	}
 
-		ch, err := conn.Channel()
-		if err != nil {
-			conn.Close()
-			pool.Close()
+	// Listeners are only added when the connection or channel is new, a
+	// reconnect that keeps the connection keeps its listener too
+	var connClosed, chClosed chan *amqp.Error
+	var watchedConn *amqp.Connection
+	var watchedCh *amqp.Channel
 
-			return nil, fmt.Errorf("failed to create RabbitMQ channel %d: %w\nAction: Check RabbitMQ channel limits and service health", i, err)
+	for {
+		if conn != watchedConn {
+			connClosed = conn.NotifyClose(make(chan *amqp.Error, 1))
+			watchedConn = conn
+		}
+		if ch != watchedCh {
+			chClosed = ch.NotifyClose(make(chan *amqp.Error, 1))
+			watchedCh = ch
 		}
 
-		err = ch.Qos(1000, 0, false)
-		if err != nil {
-			ch.Close()
-			conn.Close()
-			pool.Close()
-			return nil, fmt.Errorf("failed to set QoS for channel %d: %w\nAction: Check RabbitMQ configuration allows prefetch settings", i, err)
+		var reason *amqp.Error
+		select {
+		case reason = <-connClosed:
+		case reason = <-chClosed:
+		case <-p.done:
+			return
 		}
 
-		pool.connections[i] = conn
-		pool.channels[i] = ch
+		if p.closing.Load() {
+			return
+		}
+
+		log.Printf("Channel %d is closed (%v), attempting to recreate", slot, reason)
+
+		conn, ch = p.reconnect(slot, conn)
+		if conn == nil {
+			return
+		}
+
+		p.reconnects.Add(1)
+		log.Printf("Channel %d recreated", slot)
+
+		// Broker is reachable again, send anything spooled while it was down
+		p.ReplaySpoolAsync()
 	}
+}
 
-	return pool, nil
+// reconnect retries with exponential backoff until the slot has a working
+// channel again, reusing the existing connection if only the channel died.
+// It returns nil if the pool is closed while waiting.
+func (p *ConnectionPool) reconnect(slot int, conn *amqp.Connection) (*amqp.Connection, *amqp.Channel) {
+	delay := reconnectBaseDelay
+
+	for {
+		if p.closing.Load() {
+			return nil, nil
+		}
+
+		var err error
+		if conn == nil || conn.IsClosed() {
+			conn, err = amqp.DialConfig(p.url, p.amqpConfig)
+		}
+
+		if err == nil {
+			var ch *amqp.Channel
+			ch, err = p.openChannel(conn, slot)
+			if err == nil {
+				p.slotsMu.Lock()
+				p.connections[slot] = conn
+				p.channels[slot] = ch
+				p.slotsMu.Unlock()
+				return conn, ch
+			}
+
+			// The connection is not usable, force a fresh dial next time
+			conn.Close()
+			conn = nil
+		}
+
+		log.Printf("Reconnect of channel %d failed, retrying in %v: %v", slot, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-p.done:
+			return nil, nil
+		}
+
+		delay = min(delay*2, reconnectMaxDelay)
+	}
 }
 
+// GetChannel returns an open channel, skipping slots that are reconnecting.
+// It only returns nil when every slot is down or the pool is closing.
 func (p *ConnectionPool) GetChannel() *amqp.Channel {
 	if p.closing.Load() {
 		return nil
@@ -77,27 +285,59 @@ func (p *ConnectionPool) GetChannel() *amqp.Channel {
 		return nil
 	}
 
+	p.slotsMu.RLock()
+	defer p.slotsMu.RUnlock()
+
 	// Lock-free round-robin using atomic operations
-	idx := p.current.Add(1) % uint32(p.poolSize)
-	ch := p.channels[idx]
+	start := p.current.Add(1)
+	for n := 0; n < p.poolSize; n++ {
+		idx := (start + uint32(n)) % uint32(p.poolSize)
+		ch := p.channels[idx]
 
-	// Check if channel is still open
-	if ch == nil || ch.IsClosed() {
-		if p.closing.Load() {
-			log.Printf("Channel %d is closed during pool shutdown", idx)
-		} else {
-			log.Printf("Channel %d is closed, attempting to recreate", idx)
+		if ch != nil && !ch.IsClosed() {
+			return ch
 		}
-		return nil
 	}
 
-	return ch
+	log.Printf("All %d channels are closed, waiting for reconnect", p.poolSize)
+	return nil
+}
+
+// Reconnects returns how many times a slot has been recreated.
+func (p *ConnectionPool) Reconnects() int64 {
+	return p.reconnects.Load()
+}
+
+// batchTuner returns the worker's tuner, creating it on first use.
+func (p *ConnectionPool) batchTuner(workerID int) *batchTuner {
+	p.tunersMu.Lock()
+	defer p.tunersMu.Unlock()
+
+	tuner, ok := p.tuners[workerID]
+	if !ok {
+		tuner = newBatchTuner(p.config, workerID)
+		p.tuners[workerID] = tuner
+	}
+	return tuner
 }
 
 func (p *ConnectionPool) Close() {
 	time.Sleep(500 * time.Millisecond)
 
-	p.closing.Store(true)
+	p.replayMu.Lock()
+	if p.closing.Swap(true) {
+		p.replayMu.Unlock()
+		return
+	}
+	close(p.done)
+	p.replayMu.Unlock()
+
+	// Let an in-flight spool replay stop before its channel goes away
+	p.replayWg.Wait()
+	p.watchWg.Wait()
+
+	p.slotsMu.Lock()
+	defer p.slotsMu.Unlock()
 
 	for i := 0; i < len(p.channels); i++ {
 		if p.channels[i] != nil {
@@ -124,21 +364,39 @@ type PubSub struct {
 	batchPool   *BatchPool
 
 	totalBatches     int
+	totalCarBatches  int
+	totalCarRecords  int
 	totalRecords     int
 	totalBytes       int64
 	lastFlush        time.Time
 	batchSizeBytes   int
 	batchSizeRecords int
+	flushInterval    time.Duration
+
+	// File hash and group the batch IDs are derived from, see SetBatchKey
+	batchKey string
 
-	// Data persistence for RabbitMQ failures
-	failedBatchCount   int
-	persistedBatches   int
-	maxPersistentBytes int64
+	// Adjusts the three limits above as publishing speeds up or falls behind
+	tuner                *batchTuner
+	memoryPressureEvents int
+	backlogEvents        int
 
-	// RabbitMQ failures fallback
-	consecutiveFailures    int
-	lastFailureTime        time.Time
-	maxConsecutiveFailures int
+	// Data persistence for RabbitMQ failures. These are updated from both the
+	// async publisher and the sync fallback path, which holds mu.
+	failedBatchCount atomic.Int64
+	persistedBatches atomic.Int64
+
+	// Batches lost because the spool was missing or full, and batches
+	// queued for the async publisher that it has not finished yet
+	droppedBatches atomic.Int64
+	pendingBatches atomic.Int64
+
+	// Batches the broker has taken, acked by the broker in confirm mode
+	sentBatches atomic.Int64
+	sentBytes   atomic.Int64
+
+	// Times this publisher's failure opened the pool's circuit breaker
+	breakerTrips atomic.Int64
 
 	// Async publishing
 	publishQueue   chan *publishRequest
@@ -150,39 +408,48 @@ type PubSub struct {
 }
 
 type publishRequest struct {
-	batch *TelemetryBatch
+	batch batchMessage
 	data  []byte
 	errCh chan error
 }
 
 type PublishMetrics struct {
 	TotalBatches        int
+	SentBatches         int
 	TotalRecords        int
 	TotalBytes          int64
 	CurrentBatchSize    int
+	AdaptiveBatchSize   int
+	FlushInterval       time.Duration
 	LastFlush           time.Time
 	FailedBatches       int
 	PersistedBatches    int
 	CircuitBreakerOpen  bool
+	CircuitBreakerState string
+	CircuitBreakerTrips int
 	ConsecutiveFailures int
+
+	// Times the batch was cut for heap use or a slow broker
+	MemoryPressureEvents int
+	BacklogEvents        int
 }
 
 func NewPubSub(sessionId string, sessionTime time.Time, cfg *config.Config, pool *ConnectionPool, workerId int) *PubSub {
+	tuner := pool.batchTuner(workerId)
+	batchSizeRecords, batchSizeBytes, flushInterval := tuner.limits()
 
 	ps := &PubSub{
-		pool:               pool,
-		sessionID:          sessionId,
-		sessionTime:        sessionTime,
-		config:             cfg,
-		ctx:                context.Background(),
-		batchPool:          NewBatchPool(cfg.RabbitMQBatchSize),
-		batchSizeBytes:     cfg.BatchSizeBytes,
-		batchSizeRecords:   cfg.BatchSizeRecords,
-		lastFlush:          time.Now(),
-		maxPersistentBytes: 500 * 1024 * 1024, // 500MB max persistent storage per worker
-
-		consecutiveFailures:    0,
-		maxConsecutiveFailures: 3, // Open circuit after 3 consecutive failures
+		pool:             pool,
+		sessionID:        sessionId,
+		sessionTime:      sessionTime,
+		config:           cfg,
+		ctx:              context.Background(),
+		batchPool:        NewBatchPool(cfg.RabbitMQBatchSize),
+		batchSizeBytes:   batchSizeBytes,
+		batchSizeRecords: batchSizeRecords,
+		flushInterval:    flushInterval,
+		tuner:            tuner,
+		lastFlush:        time.Now(),
 
 		// Async publishing - buffer up to 20 batches to prevent blocking
 		publishQueue: make(chan *publishRequest, 20),
@@ -254,16 +521,6 @@ func (ps *PubSub) Exec(data []map[string]interface{}) error {
 	return nil
 }
 
-func (ps *PubSub) recordRabbitMQFailure() {
-	ps.consecutiveFailures++
-	ps.lastFailureTime = time.Now()
-
-}
-
-func (ps *PubSub) recordRabbitMQSuccess() {
-	ps.consecutiveFailures = 0
-}
-
 func (ps *PubSub) AddRecord(record map[string]interface{}) error {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
@@ -278,7 +535,7 @@ func (ps *PubSub) AddRecord(record map[string]interface{}) error {
 
 	shouldFlush := len(ps.recordBatch) >= ps.batchSizeRecords ||
 		ps.totalBytes >= int64(ps.batchSizeBytes) ||
-		time.Since(ps.lastFlush) > time.Duration(ps.config.BatchTimeout)
+		time.Since(ps.lastFlush) > ps.flushInterval
 
 	if shouldFlush {
 		return ps.flushBatchInternal()
@@ -393,14 +650,15 @@ func (ps *PubSub) publishWorker() {
 	for {
 		select {
 		case req := <-ps.publishQueue:
-			log.Printf("Worker %d: Processing batch %s from async queue", ps.workerID, req.batch.BatchId)
+			log.Printf("Worker %d: Processing batch %s from async queue", ps.workerID, req.batch.GetBatchId())
 			err := ps.doPublish(req.batch, req.data)
 			if err != nil {
 				log.Printf("Worker %d: ERROR publishing batch %s asynchronously: %v",
-					ps.workerID, req.batch.BatchId, err)
+					ps.workerID, req.batch.GetBatchId(), err)
 			} else {
-				log.Printf("Worker %d: Successfully published batch %s", ps.workerID, req.batch.BatchId)
+				log.Printf("Worker %d: Successfully published batch %s", ps.workerID, req.batch.GetBatchId())
 			}
+			ps.pendingBatches.Add(-1)
 			req.errCh <- err
 		case <-ps.publishDone:
 			log.Printf("Worker %d: Draining %d remaining batches from queue", ps.workerID, len(ps.publishQueue))
@@ -409,8 +667,9 @@ func (ps *PubSub) publishWorker() {
 				err := ps.doPublish(req.batch, req.data)
 				if err != nil {
 					log.Printf("Worker %d: ERROR publishing batch %s during shutdown: %v",
-						ps.workerID, req.batch.BatchId, err)
+						ps.workerID, req.batch.GetBatchId(), err)
 				}
+				ps.pendingBatches.Add(-1)
 				req.errCh <- err
 			}
 			return
@@ -419,21 +678,26 @@ func (ps *PubSub) publishWorker() {
 }
 
 // doPublish performs the actual RabbitMQ publish operation
-func (ps *PubSub) doPublish(batch *TelemetryBatch, data []byte) error {
+func (ps *PubSub) doPublish(batch batchMessage, data []byte) error {
+	// While the breaker is open the broker is not tried at all
+	if !ps.pool.breaker.allow() {
+		return ps.persistBatch(batch, data)
+	}
+
 	maxRetries := 3
-	if ps.isShuttingDown.Load() {
+	if state, _ := ps.pool.breaker.snapshot(); ps.isShuttingDown.Load() || state == breakerHalfOpen {
 		maxRetries = 1
 	}
 
+	start := time.Now()
 	for retry := 0; retry < maxRetries; retry++ {
 		ch := ps.pool.GetChannel()
 		if ch == nil {
 			// During shutdown, channels should still be available until all publishers finish
 			if ps.isShuttingDown.Load() {
-				// This shouldn't happen anymore since we wait for all publishers
 				log.Printf("Worker %d: ERROR - channel unavailable during shutdown for batch %s",
-					ps.workerID, batch.BatchId)
-				return fmt.Errorf("channel unavailable during shutdown")
+					ps.workerID, batch.GetBatchId())
+				break
 			}
 
 			// Normal operation - retry
@@ -441,32 +705,21 @@ func (ps *PubSub) doPublish(batch *TelemetryBatch, data []byte) error {
 				time.Sleep(time.Duration(retry+1) * 100 * time.Millisecond)
 				continue
 			}
-			return fmt.Errorf("failed to get RabbitMQ channel after %d retries\nAction: Check RabbitMQ service health and connection pool size", maxRetries)
+			log.Printf("Worker %d: Failed to get RabbitMQ channel after %d retries", ps.workerID, maxRetries)
+			break
 		}
 
-		// Reduce timeout from 10s to 1s for fast-fail
-		ctx, cancel := context.WithTimeout(ps.ctx, 1*time.Second)
-
-		err := ch.PublishWithContext(ctx, "telemetry_topic", "telemetry.ticks", false, false,
-			amqp.Publishing{
-				ContentType:  "application/x-protobuf",
-				Body:         data,
-				DeliveryMode: amqp.Transient,
-				Timestamp:    time.Now(),
-				MessageId:    batch.BatchId,
-				Headers: amqp.Table{
-					"worker_id":    ps.workerID,
-					"record_count": len(batch.Records),
-					"batch_size":   len(data),
-					"format":       "protobuf",
-				},
-			})
-
-		cancel()
-
+		err := ps.pool.publishBatch(ps.ctx, ch, batch, data, ps.workerID)
 		if err == nil {
-			// Success! Record this and reset circuit breaker
-			ps.recordRabbitMQSuccess()
+			ps.sentBatches.Add(1)
+			ps.sentBytes.Add(int64(len(data)))
+			ps.tuner.observeLatency(time.Since(start))
+
+			// If we were failing before, the broker is back so replay
+			// anything spooled.
+			if ps.pool.breaker.success() {
+				ps.pool.ReplaySpoolAsync()
+			}
 			return nil
 		}
 
@@ -480,18 +733,92 @@ func (ps *PubSub) doPublish(batch *TelemetryBatch, data []byte) error {
 	}
 
 	// If we reach here, RabbitMQ publish failed completely
-	// Record the failure for circuit breaker
-	ps.recordRabbitMQFailure()
+	if ps.pool.breaker.failure() {
+		ps.breakerTrips.Add(1)
+	}
 
-	log.Printf("Worker %d: Batch %s persisted to disk after RabbitMQ failure (consecutive failures: %d)",
-		ps.workerID, batch.BatchId, ps.consecutiveFailures)
+	return ps.persistBatch(batch, data)
+}
 
-	// Periodically clean up old batches
-	ps.mu.Lock()
-	ps.failedBatchCount++
-	ps.mu.Unlock()
+// persistBatch writes a batch that could not be published to the disk spool
+// so it can be replayed later. The batch is only lost if the spool is
+// missing or full, in which case an error is returned.
+func (ps *PubSub) persistBatch(batch batchMessage, data []byte) error {
+	ps.failedBatchCount.Add(1)
+
+	if ps.pool.spool == nil {
+		ps.droppedBatches.Add(1)
+		log.Printf("Worker %d: Batch %s dropped after RabbitMQ failure, no spool configured",
+			ps.workerID, batch.GetBatchId())
+		return fmt.Errorf("batch %s could not be published and no spool is configured\nAction: Check RabbitMQ service health and SPOOL_DIR", batch.GetBatchId())
+	}
 
-	return nil // Don't return error since we've handled it via persistence
+	if err := ps.pool.spool.Write(batch.GetBatchId(), data); err != nil {
+		ps.droppedBatches.Add(1)
+		log.Printf("Worker %d: Batch %s dropped, could not persist to disk: %v",
+			ps.workerID, batch.GetBatchId(), err)
+		return fmt.Errorf("failed to spool batch %s: %w", batch.GetBatchId(), err)
+	}
+
+	ps.persistedBatches.Add(1)
+	metrics.BatchesSpooledTotal.Inc()
+
+	state, failures := ps.pool.breaker.snapshot()
+	log.Printf("Worker %d: Batch %s persisted to disk after RabbitMQ failure (consecutive failures: %d, circuit %s)",
+		ps.workerID, batch.GetBatchId(), failures, state)
+
+	return nil
+}
+
+// publishBatch sends a marshalled batch to the telemetry exchange, routed by
+// its message type.
+// In confirm mode it only returns nil once the broker has acked the batch,
+// a nack or a missing confirm is returned as an error so the caller retries.
+func (p *ConnectionPool) publishBatch(ctx context.Context, ch *amqp.Channel, batch batchMessage, data []byte, workerID int) error {
+	deliveryMode := amqp.Transient
+	if p.persistent {
+		deliveryMode = amqp.Persistent
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/x-protobuf",
+		Body:         data,
+		DeliveryMode: deliveryMode,
+		Timestamp:    time.Now(),
+		MessageId:    batch.GetBatchId(),
+		Headers: amqp.Table{
+			"worker_id":    workerID,
+			"record_count": recordCount(batch),
+			"batch_size":   len(data),
+			"format":       "protobuf",
+		},
+	}
+
+	if !p.confirms {
+		// Reduce timeout from 10s to 1s for fast-fail
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
+		defer cancel()
+
+		return ch.PublishWithContext(ctx, "telemetry_topic", routingKey(batch), false, false, msg)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.confirmTimeout)
+	defer cancel()
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, "telemetry_topic", routingKey(batch), false, false, msg)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("no confirm for batch %s within %v: %w", batch.GetBatchId(), p.confirmTimeout, err)
+	}
+	if !acked {
+		return fmt.Errorf("%w: %s", ErrNacked, batch.GetBatchId())
+	}
+
+	return nil
 }
 
 func (ps *PubSub) flushBatchInternal() error {
@@ -501,7 +828,7 @@ func (ps *PubSub) flushBatchInternal() error {
 
 	batch := &TelemetryBatch{
 		Records:   ps.recordBatch,
-		BatchId:   fmt.Sprintf("batch_%d_%d_%d", ps.workerID, ps.totalBatches, time.Now().UnixNano()),
+		BatchId:   ps.batchID("batch_", ps.totalRecords-len(ps.recordBatch), len(ps.recordBatch), ps.totalBatches),
 		SessionId: ps.sessionID,
 		WorkerId:  uint32(ps.workerID),
 		Timestamp: timestamppb.New(time.Now()),
@@ -512,14 +839,35 @@ func (ps *PubSub) flushBatchInternal() error {
 		return fmt.Errorf("failed to marshal protobuf batch: %w\nAction: This is an internal error - check telemetry data validity", err)
 	}
 
+	err = ps.publish(batch, data)
+
+	// Clear batch regardless of error (error is handled via persistence)
+	ps.recordBatch = ps.recordBatch[:0]
+	ps.totalBytes = 0
+	ps.totalBatches++
+	ps.lastFlush = time.Now()
+	ps.tune()
+
+	return err
+}
+
+// tune picks up new batch limits after a flush. Called with mu held.
+func (ps *PubSub) tune() {
+	switch ps.tuner.adjust(len(ps.publishQueue), cap(ps.publishQueue)) {
+	case memoryPressure:
+		ps.memoryPressureEvents++
+	case backlogPressure:
+		ps.backlogEvents++
+	}
+	ps.batchSizeRecords, ps.batchSizeBytes, ps.flushInterval = ps.tuner.limits()
+}
+
+// publish hands a marshalled batch to the async publisher, publishing it
+// inline during shutdown or when the queue stays full.
+func (ps *PubSub) publish(batch batchMessage, data []byte) error {
 	// During shutdown, publish synchronously to avoid queuing delays
 	if ps.isShuttingDown.Load() {
-		err := ps.doPublish(batch, data)
-		ps.recordBatch = ps.recordBatch[:0]
-		ps.totalBytes = 0
-		ps.totalBatches++
-		ps.lastFlush = time.Now()
-		return err
+		return ps.doPublish(batch, data)
 	}
 
 	// Try async publishing first (non-blocking if queue has space)
@@ -529,32 +877,42 @@ func (ps *PubSub) flushBatchInternal() error {
 		errCh: make(chan error, 1),
 	}
 
+	ps.pendingBatches.Add(1)
 	select {
 	case ps.publishQueue <- req:
-		// Successfully queued for async publishing
-		// Clear batch immediately so parser can continue
-		ps.recordBatch = ps.recordBatch[:0]
-		ps.totalBytes = 0
-		ps.totalBatches++
-		ps.lastFlush = time.Now()
-
 		// Don't wait for result - let it publish async
-		// Errors are logged by the async worker
+		// Errors are logged by the async worker and reported by Close
 		return nil
 
 	case <-time.After(100 * time.Millisecond):
 		// Queue is full/slow - do sync publish to avoid blocking parser too long
+		ps.pendingBatches.Add(-1)
 		log.Printf("Worker %d: Publish queue full, falling back to sync publish", ps.workerID)
-		err := ps.doPublish(batch, data)
+		return ps.doPublish(batch, data)
+	}
+}
 
-		// Clear batch regardless of error (error is handled via persistence)
-		ps.recordBatch = ps.recordBatch[:0]
-		ps.totalBytes = 0
-		ps.totalBatches++
-		ps.lastFlush = time.Now()
+// SetBatchKey makes batch IDs deterministic: the key, normally the file
+// hash and group number, plus the offset and record count of the batch. A
+// re-ingest of the same file then produces the same IDs and telemetryService
+// can skip batches it has already written.
+// Only holds while batch boundaries repeat, which is why the time based
+// flush is skipped for keyed publishers. A batch that MEMORY_TUNING cut to
+// another size gets a new ID and is written, QuestDB's DEDUP keys catch the
+// overlapping rows instead.
+func (ps *PubSub) SetBatchKey(key string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.batchKey = key
+}
 
-		return err
+// batchID is prefix + key + record offset + record count, or the worker,
+// sequence and time when no key is set.
+func (ps *PubSub) batchID(prefix string, offset, count, seq int) string {
+	if ps.batchKey != "" {
+		return fmt.Sprintf("%s%s_%d_%d", prefix, ps.batchKey, offset, count)
 	}
+	return fmt.Sprintf("%s%d_%d_%d", prefix, ps.workerID, seq, time.Now().UnixNano())
 }
 
 func (ps *PubSub) FlushBatch() error {
@@ -563,13 +921,18 @@ func (ps *PubSub) FlushBatch() error {
 	return ps.flushBatchInternal()
 }
 
+// Close flushes the last batch and waits for the async publisher. It
+// returns an error if any batch was neither sent nor spooled, including
+// batches still queued when the wait times out.
 func (ps *PubSub) Close() error {
 	// Mark as shutting down to skip retries/delays
 	ps.isShuttingDown.Store(true)
 
 	// Flush any remaining batches
+	var errs []error
 	if err := ps.FlushBatch(); err != nil {
 		log.Printf("Worker %d: Error flushing final batch: %v", ps.workerID, err)
+		errs = append(errs, err)
 	}
 
 	// Signal async publisher to shut down
@@ -587,26 +950,41 @@ func (ps *PubSub) Close() error {
 		// Normal shutdown completed
 	case <-time.After(4 * time.Second):
 		// Timeout - queue taking too long, abandon remaining messages
-		// Silently continue - messages may be lost
+		if abandoned := ps.pendingBatches.Load(); abandoned > 0 {
+			errs = append(errs, fmt.Errorf("%d batches were still queued after 4s and were abandoned\nAction: Check RabbitMQ service health, the file is sent again on the next run", abandoned))
+		}
 	}
 
-	// Close completes silently - stats available via GetMetrics()
-	return nil
+	if dropped := ps.droppedBatches.Load(); dropped > 0 {
+		errs = append(errs, fmt.Errorf("%d batches could not be published or spooled\nAction: Check RabbitMQ service health and SPOOL_DIR", dropped))
+	}
+	return errors.Join(errs...)
 }
 
 func (ps *PubSub) GetMetrics() PublishMetrics {
+	state, failures := ps.pool.breaker.snapshot()
+
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
 	return PublishMetrics{
 		TotalBatches:        ps.totalBatches,
+		SentBatches:         int(ps.sentBatches.Load()),
 		TotalRecords:        ps.totalRecords,
-		TotalBytes:          ps.totalBytes,
+		TotalBytes:          ps.sentBytes.Load(),
 		CurrentBatchSize:    len(ps.recordBatch),
+		AdaptiveBatchSize:   ps.batchSizeRecords,
+		FlushInterval:       ps.flushInterval,
 		LastFlush:           ps.lastFlush,
-		FailedBatches:       ps.failedBatchCount,
-		PersistedBatches:    ps.persistedBatches,
-		ConsecutiveFailures: ps.consecutiveFailures,
+		FailedBatches:       int(ps.failedBatchCount.Load()),
+		PersistedBatches:    int(ps.persistedBatches.Load()),
+		CircuitBreakerOpen:  state != breakerClosed,
+		CircuitBreakerState: state.String(),
+		CircuitBreakerTrips: int(ps.breakerTrips.Load()),
+		ConsecutiveFailures: failures,
+
+		MemoryPressureEvents: ps.memoryPressureEvents,
+		BacklogEvents:        ps.backlogEvents,
 	}
 }
 
@@ -618,7 +996,9 @@ func (ps *PubSub) GetDisplayMetrics() map[string]interface{} {
 		"batches_sent":   ps.totalBatches,
 		"records_send":   ps.totalRecords,
 		"queue_size":     len(ps.publishQueue),
-		"failed_batches": ps.failedBatchCount,
+		"queue_capacity": cap(ps.publishQueue),
+		"batch_size":     ps.batchSizeRecords,
+		"failed_batches": int(ps.failedBatchCount.Load()),
 	}
 }
 
@@ -0,0 +1,53 @@
+package messaging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		batchKey string
+		prefix   string
+		offset   int
+		count    int
+		seq      int
+		want     string
+	}{
+		{"Keyed", "0a1b_2", "batch_", 1000, 500, 3, "batch_0a1b_2_1000_500"},
+		{"KeyedCars", "0a1b_2", "cars_", 1000, 60, 3, "cars_0a1b_2_1000_60"},
+		{"KeyedIgnoresSeq", "0a1b_2", "batch_", 1000, 500, 9, "batch_0a1b_2_1000_500"},
+		{"Unkeyed", "", "batch_", 1000, 500, 3, "batch_1_3_"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := &PubSub{batchKey: tc.batchKey, workerID: 1}
+
+			got := ps.batchID(tc.prefix, tc.offset, tc.count, tc.seq)
+			if tc.batchKey == "" {
+				if !strings.HasPrefix(got, tc.want) {
+					t.Errorf("batchID = %s, want prefix %s", got, tc.want)
+				}
+				return
+			}
+			if got != tc.want {
+				t.Errorf("batchID = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBatchIDDistinguishesCounts(t *testing.T) {
+	ps := &PubSub{batchKey: "0a1b_2", workerID: 1}
+
+	// A resent tick batch and a car batch at the same offset must not be
+	// mistaken for each other by the consumer's AppliedBatches check
+	if ps.batchID("batch_", 0, 500, 0) == ps.batchID("batch_", 0, 250, 0) {
+		t.Errorf("batches of different sizes at the same offset share an ID")
+	}
+	if ps.batchID("batch_", 0, 500, 0) == ps.batchID("cars_", 0, 500, 0) {
+		t.Errorf("tick and car batches at the same offset share an ID")
+	}
+}
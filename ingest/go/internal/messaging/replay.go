@@ -0,0 +1,101 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/metrics"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/spool"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetSpool enables disk persistence for batches that fail to publish.
+func (p *ConnectionPool) SetSpool(s *spool.Spool) {
+	p.spool = s
+}
+
+// ReplaySpoolAsync replays the spool in the background. It is a no-op if a
+// replay is already running or the pool is closing.
+func (p *ConnectionPool) ReplaySpoolAsync() {
+	if p.spool == nil {
+		return
+	}
+
+	p.replayMu.Lock()
+	if p.closing.Load() {
+		p.replayMu.Unlock()
+		return
+	}
+	p.replayWg.Add(1)
+	p.replayMu.Unlock()
+
+	go func() {
+		defer p.replayWg.Done()
+
+		sent, err := p.ReplaySpool()
+		if err != nil {
+			log.Printf("Spool replay stopped after %d batches: %v", sent, err)
+		} else if sent > 0 {
+			log.Printf("Replayed %d spooled batches to RabbitMQ", sent)
+		}
+	}()
+}
+
+// ReplaySpool publishes spooled batches oldest first and removes each one
+// once the broker has taken it. It stops at the first failure so the rest
+// stay on disk for the next attempt.
+func (p *ConnectionPool) ReplaySpool() (int, error) {
+	if p.spool == nil {
+		return 0, nil
+	}
+
+	if !p.replaying.CompareAndSwap(false, true) {
+		return 0, nil
+	}
+	defer p.replaying.Store(false)
+
+	entries, err := p.spool.List()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, entry := range entries {
+		if p.closing.Load() {
+			return sent, nil
+		}
+
+		data, err := p.spool.Read(entry)
+		if err != nil {
+			return sent, fmt.Errorf("failed to read spooled batch %s: %w", entry.BatchID, err)
+		}
+
+		batch := newSpooledBatch(entry.BatchID)
+		if err := proto.Unmarshal(data, batch); err != nil {
+			log.Printf("Removing corrupt spooled batch %s: %v", entry.BatchID, err)
+			if err := p.spool.Remove(entry); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		ch := p.GetChannel()
+		if ch == nil {
+			return sent, fmt.Errorf("no RabbitMQ channel available\nAction: Check RabbitMQ service health, batches remain in %s", p.spool.Dir())
+		}
+
+		if err := p.publishBatch(context.Background(), ch, batch, data, int(batch.GetWorkerId())); err != nil {
+			return sent, fmt.Errorf("failed to replay batch %s: %w", entry.BatchID, err)
+		}
+
+		if err := p.spool.Remove(entry); err != nil {
+			return sent, fmt.Errorf("replayed batch %s but could not remove it from the spool: %w", entry.BatchID, err)
+		}
+
+		sent++
+		metrics.BatchesReplayedTotal.Inc()
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TicksRoutingKey   = "telemetry.ticks"
+	CarsRoutingKey    = "telemetry.cars"
+	SessionRoutingKey = "telemetry.session"
+
+	// Batch ID prefixes mark non tick messages so a spooled batch can be
+	// decoded as the right message type on replay.
+	CarBatchPrefix     = "cars_"
+	SessionBatchPrefix = "session_"
+)
+
+// batchMessage is implemented by every message published to the exchange
+type batchMessage interface {
+	proto.Message
+	GetBatchId() string
+	GetWorkerId() uint32
+}
+
+func routingKey(batch batchMessage) string {
+	switch batch.(type) {
+	case *CarTelemetryBatch:
+		return CarsRoutingKey
+	case *SessionMetadata:
+		return SessionRoutingKey
+	}
+	return TicksRoutingKey
+}
+
+func recordCount(batch batchMessage) int {
+	switch b := batch.(type) {
+	case *TelemetryBatch:
+		return len(b.Records)
+	case *CarTelemetryBatch:
+		return len(b.Records)
+	}
+	return 1
+}
+
+func newSpooledBatch(batchID string) batchMessage {
+	switch {
+	case strings.HasPrefix(batchID, CarBatchPrefix):
+		return &CarTelemetryBatch{}
+	case strings.HasPrefix(batchID, SessionBatchPrefix):
+		return &SessionMetadata{}
+	}
+	return &TelemetryBatch{}
+}
@@ -0,0 +1,33 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ExecSession publishes the session metadata for this group
+func (ps *PubSub) ExecSession(meta *SessionMetadata) error {
+	if meta == nil {
+		return nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	// telemetryService skips IDs it has applied, so without a key the ID
+	// is unique per publish like the batch IDs
+	meta.BatchId = fmt.Sprintf("%s%s_%d_%d", SessionBatchPrefix, ps.sessionID, ps.workerID, time.Now().UnixNano())
+	if ps.batchKey != "" {
+		meta.BatchId = SessionBatchPrefix + ps.batchKey
+	}
+	meta.WorkerId = uint32(ps.workerID)
+
+	data, err := proto.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session metadata: %w\nAction: This is an internal error - check the session YAML in the IBT file", err)
+	}
+
+	return ps.publish(meta, data)
+}
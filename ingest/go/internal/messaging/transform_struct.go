@@ -9,7 +9,9 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func TransformStructBatch(ticks []*ibt.TelemetryTick) ([]*Telemetry, error) {
+// TransformStructBatch converts ticks to protobuf records. channels is either
+// nil or holds the manifest channels for the tick at the same index.
+func TransformStructBatch(ticks []*ibt.TelemetryTick, channels []map[string]float64) ([]*Telemetry, error) {
 	result := make([]*Telemetry, len(ticks))
 
 	for i, tick := range ticks {
@@ -64,12 +66,16 @@ func TransformStructBatch(ticks []*ibt.TelemetryTick) ([]*Telemetry, error) {
 
 			TickTime: timestamppb.New(tick.TickTime),
 		}
+
+		if i < len(channels) {
+			result[i].Channels = channels[i]
+		}
 	}
 
 	return result, nil
 }
 
-func (ps *PubSub) ExecStructs(ticks []*ibt.TelemetryTick) error {
+func (ps *PubSub) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
 	if len(ticks) == 0 {
 		return nil
 	}
@@ -80,35 +86,42 @@ func (ps *PubSub) ExecStructs(ticks []*ibt.TelemetryTick) error {
 	}
 
 	// Transform batch (no lock needed - pure transformation)
-	protoTicks, err := TransformStructBatch(ticks)
+	protoTicks, err := TransformStructBatch(ticks, channels)
 	if err != nil {
 		return fmt.Errorf("failed to transform struct batch: %w", err)
 	}
 
-	// Pre-calculate batch size ONCE instead of per-tick
 	// Use fixed estimate to avoid expensive proto.Size() calls
 	const estimatedTickSize = 512
-	batchSize := int64(len(protoTicks) * estimatedTickSize)
 
 	// Lock ONCE for the entire batch operation
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 
-	// Check if adding this batch would exceed limits - flush first if needed
-	wouldExceedRecords := len(ps.recordBatch)+len(protoTicks) >= ps.batchSizeRecords
-	wouldExceedBytes := ps.totalBytes+batchSize >= int64(ps.batchSizeBytes)
-	shouldFlushTime := time.Since(ps.lastFlush) > time.Duration(ps.config.BatchTimeout)
+	// The tuner may have cut the batch below the loader's chunk size, so
+	// split the chunk across as many batches as it takes
+	for len(protoTicks) > 0 {
+		full := len(ps.recordBatch) >= ps.batchSizeRecords ||
+			ps.totalBytes+estimatedTickSize > int64(ps.batchSizeBytes) ||
+			(ps.batchKey == "" && time.Since(ps.lastFlush) > ps.flushInterval)
+
+		if full && len(ps.recordBatch) > 0 {
+			if err := ps.flushBatchInternal(); err != nil {
+				return err
+			}
+		}
 
-	if (wouldExceedRecords || wouldExceedBytes || shouldFlushTime) && len(ps.recordBatch) > 0 {
-		if err := ps.flushBatchInternal(); err != nil {
-			return err
+		room := ps.batchSizeRecords - len(ps.recordBatch)
+		if roomBytes := (int64(ps.batchSizeBytes) - ps.totalBytes) / estimatedTickSize; roomBytes < int64(room) {
+			room = int(roomBytes)
 		}
-	}
+		n := min(len(protoTicks), max(room, 1))
 
-	// Append entire batch at once (fast slice append)
-	ps.recordBatch = append(ps.recordBatch, protoTicks...)
-	ps.totalRecords += len(protoTicks)
-	ps.totalBytes += batchSize
+		ps.recordBatch = append(ps.recordBatch, protoTicks[:n]...)
+		ps.totalRecords += n
+		ps.totalBytes += int64(n * estimatedTickSize)
+		protoTicks = protoTicks[n:]
+	}
 
 	return nil
 }
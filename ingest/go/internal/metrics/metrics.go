@@ -47,3 +47,80 @@ var (
 		Help: "Current depth of the file processing queue",
 	})
 )
+
+var (
+	// Spool metrics
+	BatchesSpooledTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingest_batches_spooled_total",
+		Help: "Total number of batches written to the disk spool after a publish failure",
+	})
+
+	BatchesReplayedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingest_batches_replayed_total",
+		Help: "Total number of spooled batches successfully replayed to RabbitMQ",
+	})
+)
+
+var (
+	// Per worker metrics, labelled by worker ID
+	WorkerFilesProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingest_worker_files_processed_total",
+		Help: "Total number of IBT files processed by each worker",
+	}, []string{"worker"})
+
+	WorkerRecordsProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingest_worker_records_processed_total",
+		Help: "Total number of telemetry records processed by each worker",
+	}, []string{"worker"})
+
+	FileErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingest_file_errors_total",
+		Help: "Failed file attempts by kind: bad_file, parse, io, broker_unavailable, timeout, cancelled or unknown",
+	}, []string{"kind"})
+
+	FilesQuarantinedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingest_files_quarantined_total",
+		Help: "Total number of broken files moved to the quarantine list",
+	})
+
+	WorkerErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingest_worker_errors_total",
+		Help: "Total number of failed file attempts by each worker",
+	}, []string{"worker"})
+
+	WorkerBusy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingest_worker_busy",
+		Help: "1 while the worker is processing a file, 0 when idle",
+	}, []string{"worker"})
+
+	WorkerProcessingRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingest_worker_processing_rate",
+		Help: "Records per second of the last file each worker finished",
+	}, []string{"worker"})
+)
+
+var (
+	// Publish failure metrics
+	PublishFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingest_publish_failures_total",
+		Help: "Total number of batches that failed to publish",
+	})
+
+	BatchesPersistedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingest_batches_persisted_total",
+		Help: "Total number of batches persisted locally instead of published",
+	})
+)
+
+var (
+	// Adaptive batching metrics
+	AdaptiveBatchRecords = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ingest_adaptive_batch_records",
+		Help: "Batch size in records each worker is currently publishing with",
+	}, []string{"worker"})
+
+	PressureEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingest_pressure_events_total",
+		Help: "Times the batch size was cut, by kind: memory or backlog",
+	}, []string{"kind"})
+)
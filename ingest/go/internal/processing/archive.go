@@ -0,0 +1,236 @@
+package processing
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var archiveExtensions = []string{".zip", ".tar.gz", ".tgz"}
+
+func isArchive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// extract unpacks the IBT files in an archive into ARCHIVE_CACHE_DIR and
+// returns them. Each version of an archive is unpacked once into a folder
+// keyed by its path, size and mtime, and the files keep the mtime from the
+// archive so the ledger and quarantine see the same file every scan. An
+// archive that expands past ARCHIVE_MAX_BYTES is not extracted.
+func (d *Directory) extract(path string, info os.FileInfo) ([]DiscoveredFile, error) {
+	dest := filepath.Join(d.archiveDir, archiveKey(path, info))
+
+	if _, err := os.Stat(dest); os.IsNotExist(err) {
+		tmp := dest + ".tmp"
+		if err := os.RemoveAll(tmp); err != nil {
+			return nil, fmt.Errorf("failed to clear %s: %w\nAction: Check ARCHIVE_CACHE_DIR is writable", tmp, err)
+		}
+
+		var count int
+		if strings.HasSuffix(strings.ToLower(path), ".zip") {
+			count, err = extractZip(path, tmp, d.archiveMaxBytes)
+		} else {
+			count, err = extractTarGz(path, tmp, d.archiveMaxBytes)
+		}
+		if err != nil {
+			os.RemoveAll(tmp)
+			return nil, err
+		}
+
+		if err := os.MkdirAll(tmp, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w\nAction: Check ARCHIVE_CACHE_DIR is writable", tmp, err)
+		}
+		if err := os.Rename(tmp, dest); err != nil {
+			return nil, fmt.Errorf("failed to move extracted files to %s: %w\nAction: Check ARCHIVE_CACHE_DIR is writable", dest, err)
+		}
+
+		d.logger.Info("Extracted archive",
+			zap.String("archive", path),
+			zap.Int("ibt_files", count),
+			zap.String("path", dest))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to check archive cache %s: %w", dest, err)
+	}
+
+	var files []DiscoveredFile
+	err := filepath.WalkDir(dest, func(member string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !IsIBTFile(entry.Name()) {
+			return nil
+		}
+		files = append(files, DiscoveredFile{Path: member, Entry: entry, Archive: path})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list extracted files in %s: %w", dest, err)
+	}
+	return files, nil
+}
+
+// pruneArchives removes the cache folders of archives that were not seen
+// in this scan, because they changed or are gone, and any extraction left
+// half done
+func (d *Directory) pruneArchives(current map[string]bool) {
+	entries, err := os.ReadDir(d.archiveDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || current[entry.Name()] {
+			continue
+		}
+
+		path := filepath.Join(d.archiveDir, entry.Name())
+		if err := os.RemoveAll(path); err != nil {
+			d.logger.Warn("Could not remove stale archive cache",
+				zap.String("path", path),
+				zap.Error(err),
+				zap.String("action", "Check ARCHIVE_CACHE_DIR is writable"))
+			continue
+		}
+		d.logger.Debug("Removed stale archive cache", zap.String("path", path))
+	}
+}
+
+// archiveKey names the cache folder for one version of an archive
+func archiveKey(path string, info os.FileInfo) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d", abs, info.Size(), info.ModTime().UnixNano())))
+
+	name := filepath.Base(path)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			name = name[:len(name)-len(ext)]
+			break
+		}
+	}
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
+
+// errArchiveTooLarge stops an extraction at ARCHIVE_MAX_BYTES
+var errArchiveTooLarge = errors.New("archive expands past ARCHIVE_MAX_BYTES")
+
+func extractZip(path, dest string, maxBytes int64) (int, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open zip %s: %w", path, err)
+	}
+	defer r.Close()
+
+	count := 0
+	remaining := maxBytes
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !IsIBTFile(f.Name) {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return count, fmt.Errorf("failed to read %s in %s: %w", f.Name, path, err)
+		}
+		err = writeMember(dest, f.Name, rc, f.Modified, &remaining)
+		rc.Close()
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func extractTarGz(path, dest string, maxBytes int64) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open archive %s: %w", path, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read gzip %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	count := 0
+	remaining := maxBytes
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to read tar %s: %w", path, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !IsIBTFile(hdr.Name) {
+			continue
+		}
+
+		if err := writeMember(dest, hdr.Name, tr, hdr.ModTime, &remaining); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// writeMember copies an archive member below dest, refusing names that
+// would land outside it. remaining is the bytes the archive may still
+// expand to, the member is counted against it.
+func writeMember(dest, name string, r io.Reader, modTime time.Time, remaining *int64) error {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("archive member %q points outside the archive\nAction: Repack the archive with relative paths", name)
+	}
+
+	target := filepath.Join(dest, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w\nAction: Check ARCHIVE_CACHE_DIR is writable", filepath.Dir(target), err)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w\nAction: Check ARCHIVE_CACHE_DIR is writable", target, err)
+	}
+	written, err := io.Copy(out, io.LimitReader(r, *remaining+1))
+	if err != nil {
+		out.Close()
+		return fmt.Errorf("failed to extract %s: %w\nAction: Check the archive is complete and there is enough disk space", name, err)
+	}
+	if written > *remaining {
+		out.Close()
+		return fmt.Errorf("failed to extract %s: %w\nAction: Raise ARCHIVE_MAX_BYTES if the archive is genuine", name, errArchiveTooLarge)
+	}
+	*remaining -= written
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	if !modTime.IsZero() {
+		return os.Chtimes(target, modTime, modTime)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package processing
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type member struct {
+	name string
+	body string
+}
+
+var memberTime = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func writeZip(t *testing.T, path string, members []member) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", path, err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for _, m := range members {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: m.name, Method: zip.Deflate, Modified: memberTime})
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", m.name, err)
+		}
+		if _, err := w.Write([]byte(m.body)); err != nil {
+			t.Fatalf("Failed to write %s: %v", m.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close %s: %v", path, err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, members []member) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", path, err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, m := range members {
+		hdr := &tar.Header{Name: m.name, Mode: 0o644, Size: int64(len(m.body)), ModTime: memberTime, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Failed to add %s: %v", m.name, err)
+		}
+		if _, err := tw.Write([]byte(m.body)); err != nil {
+			t.Fatalf("Failed to write %s: %v", m.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip: %v", err)
+	}
+}
+
+func TestWriteMember(t *testing.T) {
+	testCases := []struct {
+		name          string
+		member        string
+		body          string
+		remaining     int64
+		wantErr       string
+		wantRemaining int64
+	}{
+		{"Plain", "session.ibt", "telemetry", 100, "", 91},
+		{"Nested", "2026/march/session.ibt", "telemetry", 100, "", 91},
+		{"DotInside", "2026/../session.ibt", "telemetry", 100, "", 91},
+		{"ExactlyFits", "session.ibt", "telemetry", 9, "", 0},
+		{"ParentDir", "../session.ibt", "telemetry", 100, "points outside the archive", 100},
+		{"DeepParentDir", "2026/../../session.ibt", "telemetry", 100, "points outside the archive", 100},
+		{"OnlyParent", "..", "telemetry", 100, "points outside the archive", 100},
+		{"Absolute", "/etc/session.ibt", "telemetry", 100, "points outside the archive", 100},
+		{"TooLarge", "session.ibt", "telemetry", 8, "ARCHIVE_MAX_BYTES", 8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			dest := filepath.Join(root, "dest")
+
+			remaining := tc.remaining
+			err := writeMember(dest, tc.member, strings.NewReader(tc.body), memberTime, &remaining)
+			if remaining != tc.wantRemaining {
+				t.Errorf("remaining = %d, want %d", remaining, tc.wantRemaining)
+			}
+
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("writeMember error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				if _, err := os.Stat(filepath.Join(root, "session.ibt")); !os.IsNotExist(err) {
+					t.Errorf("member was written outside dest")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("writeMember failed: %v", err)
+			}
+
+			target := filepath.Join(dest, filepath.Clean(filepath.FromSlash(tc.member)))
+			info, err := os.Stat(target)
+			if err != nil {
+				t.Fatalf("member not written to %s: %v", target, err)
+			}
+			if !info.ModTime().Equal(memberTime) {
+				t.Errorf("mtime = %v, want the archive's %v", info.ModTime(), memberTime)
+			}
+		})
+	}
+}
+
+func TestWriteMemberTooLargeError(t *testing.T) {
+	remaining := int64(4)
+	err := writeMember(t.TempDir(), "session.ibt", strings.NewReader("telemetry"), time.Time{}, &remaining)
+	if !errors.Is(err, errArchiveTooLarge) {
+		t.Errorf("writeMember error = %v, want errArchiveTooLarge", err)
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+	extractors := []struct {
+		name    string
+		ext     string
+		write   func(*testing.T, string, []member)
+		extract func(string, string, int64) (int, error)
+	}{
+		{"Zip", ".zip", writeZip, extractZip},
+		{"TarGz", ".tar.gz", writeTarGz, extractTarGz},
+	}
+
+	testCases := []struct {
+		name      string
+		members   []member
+		maxBytes  int64
+		wantCount int
+		wantFiles []string
+		wantErr   string
+	}{
+		{
+			"IBTFilesOnly",
+			[]member{{"a.ibt", "aaaa"}, {"notes.txt", "skip"}, {"laps/b.IBT", "bbbb"}, {"c.ibt.bak", "skip"}},
+			100, 2, []string{"a.ibt", filepath.Join("laps", "b.IBT")}, "",
+		},
+		{"Empty", nil, 100, 0, nil, ""},
+		{"Traversal", []member{{"a.ibt", "aaaa"}, {"../escape.ibt", "evil"}}, 100, 1, []string{"a.ibt"}, "points outside the archive"},
+		{"OverCap", []member{{"a.ibt", "aaaa"}, {"b.ibt", "bbbb"}}, 6, 1, []string{"a.ibt"}, "ARCHIVE_MAX_BYTES"},
+		{"CapCountsIBTOnly", []member{{"notes.txt", "a long text file"}, {"a.ibt", "aaaa"}}, 4, 1, []string{"a.ibt"}, ""},
+	}
+
+	for _, ex := range extractors {
+		for _, tc := range testCases {
+			t.Run(ex.name+"/"+tc.name, func(t *testing.T) {
+				root := t.TempDir()
+				archive := filepath.Join(root, "sessions"+ex.ext)
+				dest := filepath.Join(root, "cache", "sessions")
+				ex.write(t, archive, tc.members)
+
+				count, err := ex.extract(archive, dest, tc.maxBytes)
+				if tc.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+						t.Errorf("extract error = %v, want it to contain %q", err, tc.wantErr)
+					}
+				} else if err != nil {
+					t.Fatalf("extract failed: %v", err)
+				}
+				if count != tc.wantCount {
+					t.Errorf("extracted %d files, want %d", count, tc.wantCount)
+				}
+
+				for _, name := range tc.wantFiles {
+					info, err := os.Stat(filepath.Join(dest, name))
+					if err != nil {
+						t.Errorf("%s not extracted: %v", name, err)
+						continue
+					}
+					if !info.ModTime().Equal(memberTime) {
+						t.Errorf("%s mtime = %v, want %v", name, info.ModTime(), memberTime)
+					}
+				}
+				if _, err := os.Stat(filepath.Join(root, "cache", "escape.ibt")); !os.IsNotExist(err) {
+					t.Errorf("traversal member was written outside dest")
+				}
+			})
+		}
+	}
+}
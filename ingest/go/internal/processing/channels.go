@@ -0,0 +1,181 @@
+package processing
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+	"github.com/OJPARKINSON/ibt/headers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// carIdxChannels are the per-car arrays decoded when CarIdx ingest is on
+var carIdxChannels = []string{
+	"CarIdxLap",
+	"CarIdxLapDistPct",
+	"CarIdxPosition",
+	"CarIdxClassPosition",
+	"CarIdxOnPitRoad",
+	"CarIdxTrackSurface",
+	"CarIdxEstTime",
+	"CarIdxF2Time",
+}
+
+// channelReader decodes the manifest channels and CarIdx arrays over the map
+// pipeline and hands them to the loader, which stays on the struct pipeline
+// for the fixed TelemetryTick fields. ibt.Process calls its processors in
+// order for each tick, so the reader goes first and the loader picks up what
+// it read for the same tick.
+type channelReader struct {
+	loader   *loaderProcessor
+	channels []string
+
+	// CarIdx sampling, 0 disables it
+	carStride int
+	ticks     int
+}
+
+func newChannelReader(loader *loaderProcessor, channels []string, carStride int) *channelReader {
+	return &channelReader{
+		loader:    loader,
+		channels:  channels,
+		carStride: carStride,
+	}
+}
+
+func (c *channelReader) Init(session *headers.Session) error {
+	return nil
+}
+
+// Whitelist is only the extra channels, plus what is needed to match them
+// up with the loader's tick
+func (c *channelReader) Whitelist() []string {
+	names := make([]string, 0, len(c.channels)+len(carIdxChannels)+2)
+	names = append(names, "SessionTime", "SessionNum")
+	names = append(names, c.channels...)
+	if c.carStride > 0 {
+		names = append(names, carIdxChannels...)
+	}
+	return names
+}
+
+func (c *channelReader) Process(input ibt.Tick, hasNext bool, session *headers.Session) error {
+	sessionTime, _ := toFloat(input["SessionTime"])
+
+	if c.carStride > 0 {
+		if c.ticks%c.carStride == 0 {
+			sessionNum, _ := toFloat(input["SessionNum"])
+			c.loader.processCars(c.readCars(input, int32(sessionNum), sessionTime))
+		}
+		c.ticks++
+	}
+
+	if len(c.channels) == 0 {
+		return nil
+	}
+
+	extra := make(map[string]float64, len(c.channels))
+	for _, name := range c.channels {
+		if v, ok := toFloat(input[name]); ok {
+			extra[name] = v
+		}
+	}
+	c.loader.setChannels(sessionTime, extra)
+	return nil
+}
+
+// readCars builds a record for every car slot that is in the world. Slots
+// for cars that have left or never joined report a track surface of -1.
+func (c *channelReader) readCars(input ibt.Tick, sessionNum int32, sessionTime float64) []*messaging.CarTelemetry {
+	laps := toFloats(input["CarIdxLap"])
+	lapDist := toFloats(input["CarIdxLapDistPct"])
+	positions := toFloats(input["CarIdxPosition"])
+	classPositions := toFloats(input["CarIdxClassPosition"])
+	onPitRoad := toFloats(input["CarIdxOnPitRoad"])
+	surfaces := toFloats(input["CarIdxTrackSurface"])
+	estTimes := toFloats(input["CarIdxEstTime"])
+	f2Times := toFloats(input["CarIdxF2Time"])
+
+	tickTime := timestamppb.New(c.loader.tickTime(sessionTime))
+
+	cars := make([]*messaging.CarTelemetry, 0, len(surfaces))
+	for idx := range surfaces {
+		if surfaces[idx] < 0 {
+			continue
+		}
+
+		cars = append(cars, &messaging.CarTelemetry{
+			SessionId:     c.loader.subSessionID,
+			SessionNum:    strconv.Itoa(int(sessionNum)),
+			CarIdx:        uint32(idx),
+			Lap:           int32(at(laps, idx)),
+			LapDistPct:    at(lapDist, idx),
+			Position:      int32(at(positions, idx)),
+			ClassPosition: int32(at(classPositions, idx)),
+			OnPitRoad:     at(onPitRoad, idx) != 0,
+			TrackSurface:  int32(surfaces[idx]),
+			EstTime:       at(estTimes, idx),
+			F2Time:        at(f2Times, idx),
+			SessionTime:   sessionTime,
+			TickTime:      tickTime,
+		})
+	}
+
+	return cars
+}
+
+// The loader owns the batches, so there is nothing to flush here
+func (c *channelReader) FlushPendingData() error {
+	return nil
+}
+
+func (c *channelReader) Close() error {
+	return nil
+}
+
+// toFloats converts a decoded IBT array channel, element by element
+func toFloats(raw any) []float64 {
+	value := reflect.ValueOf(raw)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil
+	}
+
+	out := make([]float64, value.Len())
+	for i := range out {
+		out[i], _ = toFloat(value.Index(i).Interface())
+	}
+	return out
+}
+
+func at(values []float64, idx int) float64 {
+	if idx < len(values) {
+		return values[idx]
+	}
+	return 0
+}
+
+// toFloat converts a decoded IBT value; bitfields and bools come through as
+// integers and booleans, array channels are skipped.
+func toFloat(raw any) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -1,56 +1,315 @@
 package processing
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
 	"go.uber.org/zap"
 )
 
+// DiscoveredFile is an IBT file picked by a scan. Files from an archive
+// have been extracted, Path is the extracted copy and Archive the bundle.
+type DiscoveredFile struct {
+	Path    string
+	Entry   os.DirEntry
+	Archive string
+}
+
 type Directory struct {
-	path             string
+	roots            []string
+	recursive        bool
+	archives         bool
+	archiveDir       string
+	archiveMaxBytes  int64
 	lastScan         time.Time
 	fileAgeThreshold time.Duration
 	logger           *zap.Logger
+	config           *config.Config
+
+	// Files are inspected against the filter once per mtime, the result is
+	// remembered so a watch does not read them every scan
+	filter      Filter
+	inspector   *FileProcessor
+	inspected   map[string]inspection
+	newestFirst bool
+
+	// Files Watch has sent, by mtime, until Forget is called for them
+	seenMu sync.Mutex
+	seen   map[string]time.Time
 }
 
-func NewDir(path string, cfg *config.Config, logger *zap.Logger) *Directory {
+// inspection is whether a file matched the filter at modTime
+type inspection struct {
+	modTime time.Time
+	match   bool
+}
+
+// NewDir scans each root, and the folders below them with INGEST_RECURSIVE
+func NewDir(roots []string, cfg *config.Config, logger *zap.Logger) *Directory {
 	return &Directory{
-		path:             path,
+		roots:            roots,
+		recursive:        cfg.Recursive,
+		archives:         cfg.ScanArchives,
+		archiveDir:       cfg.ArchiveDirectory,
+		archiveMaxBytes:  cfg.ArchiveMaxBytes,
 		fileAgeThreshold: cfg.FileAgeThreshold,
 		logger:           logger,
+		config:           cfg,
+		inspected:        make(map[string]inspection),
+		seen:             make(map[string]time.Time),
 	}
 }
 
-func (d *Directory) WatchDir() []os.DirEntry {
-	d.lastScan = time.Now()
-	files, err := os.ReadDir(d.path)
+// SetFilter only returns the files whose headers match filter
+func (d *Directory) SetFilter(filter Filter) error {
+	if filter.Empty() {
+		d.filter, d.inspector = Filter{}, nil
+		return nil
+	}
+
+	inspector, err := NewFileProcessor(d.config, 0, nil)
 	if err != nil {
-		d.logger.Fatal("Could not read the given input directory", zap.Error(err))
+		return err
+	}
+	d.filter, d.inspector = filter, inspector
+	d.inspected = make(map[string]inspection)
+	return nil
+}
+
+// SetNewestFirst orders each scan by modification time, newest first, so
+// the session just driven is sent ahead of older files
+func (d *Directory) SetNewestFirst(newestFirst bool) {
+	d.newestFirst = newestFirst
+}
+
+// Watch rescans the directory every interval until ctx is cancelled and sends
+// each file once it has been untouched for the file age threshold. A file is
+// sent again if it is modified after it was first sent, or after Forget.
+func (d *Directory) Watch(ctx context.Context, interval time.Duration) <-chan DiscoveredFile {
+	out := make(chan DiscoveredFile)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			files, err := d.Scan()
+			if err != nil {
+				d.logger.Error("Could not read the watched directories",
+					zap.Strings("paths", d.roots),
+					zap.Error(err),
+					zap.String("action", "Check the directories still exist and are readable"))
+			}
+
+			for _, file := range files {
+				info, err := file.Entry.Info()
+				if err != nil {
+					continue
+				}
+
+				if !d.markSeen(file.Path, info.ModTime()) {
+					continue
+				}
+
+				select {
+				case out <- file:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// markSeen records the file as sent, false if it already was at this mtime
+func (d *Directory) markSeen(path string, modTime time.Time) bool {
+	d.seenMu.Lock()
+	defer d.seenMu.Unlock()
+
+	if seen, ok := d.seen[path]; ok && seen.Equal(modTime) {
+		return false
 	}
+	d.seen[path] = modTime
+	return true
+}
+
+// Forget lets Watch send a file again on the next scan, used for files that
+// failed without reaching the ledger or quarantine
+func (d *Directory) Forget(path string) {
+	d.seenMu.Lock()
+	defer d.seenMu.Unlock()
+	delete(d.seen, path)
+}
+
+// Scan returns the IBT files in every root, and in the archives there, that
+// have not been written to within the age threshold. A root that cannot be
+// read is logged and skipped, the error is only returned if none could be.
+func (d *Directory) Scan() ([]DiscoveredFile, error) {
+	d.lastScan = time.Now()
 
-	filesToProcess := make([]os.DirEntry, 0)
+	filesToProcess := make([]DiscoveredFile, 0)
+	modTimes := make(map[string]time.Time)
+	archiveKeys := make(map[string]bool)
+	total, filtered := 0, 0
 
-	for _, file := range files {
-		info, err := file.Info()
+	var errs []error
+	for _, root := range d.roots {
+		paths, err := d.list(root)
 		if err != nil {
-			d.logger.Warn("Could not get file info", zap.String("file", file.Name()), zap.Error(err))
+			d.logger.Error("Could not read telemetry directory",
+				zap.String("path", root),
+				zap.Error(err),
+				zap.String("action", "Check the directory exists and is readable"))
+			errs = append(errs, err)
 			continue
 		}
 
-		if info.ModTime().Before(time.Now().Add(-d.fileAgeThreshold)) {
-			filesToProcess = append(filesToProcess, file)
-		} else {
-			d.logger.Debug("Skipping recent file (still being written?)",
-				zap.Duration("age_threshold", d.fileAgeThreshold),
-				zap.String("file", file.Name()))
+		for _, path := range paths {
+			total++
+
+			info, err := os.Stat(path)
+			if err != nil {
+				d.logger.Warn("Could not get file info", zap.String("file", path), zap.Error(err))
+				continue
+			}
+
+			if !info.ModTime().Before(time.Now().Add(-d.fileAgeThreshold)) {
+				d.logger.Debug("Skipping recent file (still being written?)",
+					zap.Duration("age_threshold", d.fileAgeThreshold),
+					zap.String("file", path))
+				continue
+			}
+
+			found := []DiscoveredFile{{Path: path, Entry: fs.FileInfoToDirEntry(info)}}
+			if isArchive(path) {
+				archiveKeys[archiveKey(path, info)] = true
+				if found, err = d.extract(path, info); err != nil {
+					d.logger.Warn("Could not read archive",
+						zap.String("archive", path),
+						zap.Error(err),
+						zap.String("action", "Check the archive is a complete .zip or .tar.gz"))
+					continue
+				}
+			}
+
+			for _, file := range found {
+				if _, dup := modTimes[file.Path]; dup {
+					continue
+				}
+
+				fileInfo, err := file.Entry.Info()
+				if err != nil {
+					continue
+				}
+				if !d.selected(file.Path, fileInfo) {
+					filtered++
+					continue
+				}
+
+				filesToProcess = append(filesToProcess, file)
+				modTimes[file.Path] = fileInfo.ModTime()
+			}
 		}
 	}
 
+	if len(d.roots) > 0 && len(errs) == len(d.roots) {
+		return nil, errors.Join(errs...)
+	}
+
+	// A root that could not be read may still hold the archives
+	if d.archives && len(errs) == 0 {
+		d.pruneArchives(archiveKeys)
+	}
+
+	if d.newestFirst {
+		sort.SliceStable(filesToProcess, func(i, j int) bool {
+			return modTimes[filesToProcess[i].Path].After(modTimes[filesToProcess[j].Path])
+		})
+	}
+
 	d.lastScan = time.Now()
 	d.logger.Info("Files found for processing",
 		zap.Int("ready_files", len(filesToProcess)),
-		zap.Int("total_files", len(files)))
-	return filesToProcess
+		zap.Int("filtered_files", filtered),
+		zap.Int("total_files", total))
+	return filesToProcess, nil
+}
+
+// list returns the IBT files and archives in root, and below it when
+// recursive. The archive cache is skipped if it sits inside a root.
+func (d *Directory) list(root string) ([]string, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	archiveDir, _ := filepath.Abs(d.archiveDir)
+
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			d.logger.Warn("Could not read folder, skipping it", zap.String("path", path), zap.Error(err))
+			return fs.SkipDir
+		}
+
+		if entry.IsDir() {
+			if path == root {
+				return nil
+			}
+			if abs, _ := filepath.Abs(path); !d.recursive || abs == archiveDir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if IsIBTFile(entry.Name()) || (d.archives && isArchive(entry.Name())) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// selected checks the file's headers against the filter. Files whose
+// headers cannot be read are let through for the worker to report.
+func (d *Directory) selected(path string, info os.FileInfo) bool {
+	if d.inspector == nil {
+		return true
+	}
+	if last, ok := d.inspected[path]; ok && last.modTime.Equal(info.ModTime()) {
+		return last.match
+	}
+
+	fileInfo, err := d.inspector.Inspect(path)
+	if err != nil {
+		return true
+	}
+
+	match, reason := d.filter.Match(fileInfo)
+	d.inspected[path] = inspection{modTime: info.ModTime(), match: match}
+	if !match {
+		d.logger.Debug("Skipping filtered file",
+			zap.String("file", path),
+			zap.String("reason", reason))
+	}
+	return match
 }
@@ -0,0 +1,100 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+)
+
+// ErrorKind says what went wrong with a file, and so whether trying it
+// again can help
+type ErrorKind string
+
+const (
+	// Not an IBT file, or empty
+	KindBadFile ErrorKind = "bad_file"
+	// The headers or ticks could not be decoded
+	KindParse ErrorKind = "parse"
+	// The file could not be opened or read, e.g. still held by the sim
+	KindIO ErrorKind = "io"
+	// The sink, RabbitMQ or QuestDB, did not take the data
+	KindBroker ErrorKind = "broker_unavailable"
+	// FILE_PROCESS_TIMEOUT ran out
+	KindTimeout ErrorKind = "timeout"
+	// Ingest is shutting down
+	KindCancelled ErrorKind = "cancelled"
+	// Anything not classified above
+	KindUnknown ErrorKind = "unknown"
+)
+
+// Permanent kinds fail the same way every time until the file changes
+func (k ErrorKind) Permanent() bool {
+	return k == KindBadFile || k == KindParse
+}
+
+// Retryable kinds may succeed on a later attempt
+func (k ErrorKind) Retryable() bool {
+	return !k.Permanent() && k != KindCancelled
+}
+
+// FileError is an error from processing a file with its kind attached.
+// The message keeps the Action hint of the error it wraps.
+type FileError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *FileError) Error() string { return e.Err.Error() }
+func (e *FileError) Unwrap() error { return e.Err }
+
+func newFileError(kind ErrorKind, format string, args ...any) error {
+	return &FileError{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// classify wraps err as kind unless it already carries a kind
+func classify(kind ErrorKind, err error) error {
+	var fileErr *FileError
+	if err == nil || errors.As(err, &fileErr) {
+		return err
+	}
+	return &FileError{Kind: kind, Err: err}
+}
+
+// ioKind is KindIO for errors opening or reading the file, which a later
+// attempt can get past, and kind for anything else
+func ioKind(err error, kind ErrorKind) ErrorKind {
+	var pathErr *fs.PathError
+	var errno syscall.Errno
+	if errors.As(err, &pathErr) || errors.As(err, &errno) {
+		return KindIO
+	}
+	return kind
+}
+
+// stubsError classifies a ParseStubs failure, a file that cannot be opened
+// is retried and one that cannot be decoded is not
+func stubsError(path string, err error) error {
+	if ioKind(err, KindParse) == KindIO {
+		return newFileError(KindIO, "failed to read %v: %w\nAction: Check the file is not open in iRacing or another program, it will be retried", path, err)
+	}
+	return newFileError(KindParse, "failed to parse stubs for %v: %w\nAction: File may be corrupted or incomplete - verify file integrity", path, err)
+}
+
+// Kind returns the kind of err, context errors included
+func Kind(err error) ErrorKind {
+	var fileErr *FileError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &fileErr):
+		return fileErr.Kind
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.Is(err, context.Canceled):
+		return KindCancelled
+	default:
+		return KindUnknown
+	}
+}
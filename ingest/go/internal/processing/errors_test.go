@@ -0,0 +1,111 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+)
+
+func TestKind(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"Nil", nil, ""},
+		{"FileError", newFileError(KindBadFile, "empty file"), KindBadFile},
+		{"WrappedFileError", fmt.Errorf("worker: %w", newFileError(KindParse, "bad header")), KindParse},
+		{"Deadline", fmt.Errorf("sink: %w", context.DeadlineExceeded), KindTimeout},
+		{"Cancelled", context.Canceled, KindCancelled},
+		{"Unclassified", errors.New("boom"), KindUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Kind(tc.err); got != tc.want {
+				t.Errorf("Kind = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"Nil", nil, ""},
+		{"Plain", errors.New("publish failed"), KindBroker},
+		{"KeepsExistingKind", fmt.Errorf("wrapped: %w", newFileError(KindParse, "bad tick")), KindParse},
+		{"ContextError", context.DeadlineExceeded, KindBroker},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classify(KindBroker, tc.err)
+			if got := Kind(err); got != tc.want {
+				t.Errorf("Kind(classify) = %q, want %q", got, tc.want)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("classify lost the wrapped error %v", tc.err)
+			}
+		})
+	}
+}
+
+func TestIOKind(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "session.ibt", Err: syscall.EACCES}
+
+	testCases := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"PathError", pathErr, KindIO},
+		{"WrappedPathError", fmt.Errorf("hash: %w", pathErr), KindIO},
+		{"Errno", syscall.EBUSY, KindIO},
+		{"Other", errors.New("bad var header"), KindParse},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ioKind(tc.err, KindParse); got != tc.want {
+				t.Errorf("ioKind = %q, want %q", got, tc.want)
+			}
+			if got := Kind(stubsError("session.ibt", tc.err)); got != tc.want {
+				t.Errorf("Kind(stubsError) = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestErrorKindRetry(t *testing.T) {
+	testCases := []struct {
+		kind          ErrorKind
+		wantPermanent bool
+		wantRetryable bool
+	}{
+		{KindBadFile, true, false},
+		{KindParse, true, false},
+		{KindIO, false, true},
+		{KindBroker, false, true},
+		{KindTimeout, false, true},
+		{KindCancelled, false, false},
+		{KindUnknown, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.kind), func(t *testing.T) {
+			if got := tc.kind.Permanent(); got != tc.wantPermanent {
+				t.Errorf("Permanent = %v, want %v", got, tc.wantPermanent)
+			}
+			if got := tc.kind.Retryable(); got != tc.wantRetryable {
+				t.Errorf("Retryable = %v, want %v", got, tc.wantRetryable)
+			}
+		})
+	}
+}
@@ -6,13 +6,14 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"regexp"
-	"strconv"
-	"strings"
+	"slices"
 	"time"
 
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/channels"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
 	"github.com/OJPARKINSON/ibt"
 )
 
@@ -21,6 +22,23 @@ type FileProcessor struct {
 	workerID         int
 	pool             *messaging.ConnectionPool
 	progressCallback ProgressCallback
+
+	// Extra IBT channels from CHANNEL_MANIFEST
+	channels []string
+
+	// INGEST_TIMEZONE, for dates read from file names
+	location *time.Location
+
+	newSink SinkFactory
+}
+
+// SinkFactory creates the output for one session group
+type SinkFactory func(sessionID, trackName string, sessionTime time.Time) (sink.Sink, error)
+
+// batchKeyer is implemented by sinks that can derive batch IDs from the
+// file, messaging.PubSub does
+type batchKeyer interface {
+	SetBatchKey(key string)
 }
 
 type ProcessResult struct {
@@ -29,15 +47,38 @@ type ProcessResult struct {
 	SessionID        string
 	TrackName        string
 	MessagingMetrics *messaging.PublishMetrics
+
+	// SubSessionID of every group, and the laps found across them
+	Sessions []string
+	Laps     int
 }
 
 func NewFileProcessor(cfg *config.Config, workerID int, pool *messaging.ConnectionPool) (*FileProcessor, error) {
-	return &FileProcessor{
+	fp := &FileProcessor{
 		config:           cfg,
 		workerID:         workerID,
 		pool:             pool,
 		progressCallback: &NoOpProgressCallback{},
-	}, nil
+	}
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w\nAction: Set INGEST_TIMEZONE to Local, UTC or an IANA zone like Europe/London", cfg.Timezone, err)
+	}
+	fp.location = location
+
+	fp.newSink = func(sessionID, trackName string, sessionTime time.Time) (sink.Sink, error) {
+		return sink.New(fp.config, fp.pool, sessionID, sessionTime, fp.workerID)
+	}
+
+	if cfg.ChannelManifest != "" {
+		names, err := channels.Load(cfg.ChannelManifest)
+		if err != nil {
+			return nil, err
+		}
+		fp.channels = names
+	}
+
+	return fp, nil
 }
 
 func (fp *FileProcessor) SetProgressCallback(callback ProgressCallback) {
@@ -46,27 +87,38 @@ func (fp *FileProcessor) SetProgressCallback(callback ProgressCallback) {
 	}
 }
 
-func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string, fileEntry os.DirEntry) (*ProcessResult, error) {
-	fileName := fileEntry.Name()
+// SetSinkFactory replaces the INGEST_SINK output, used by export
+func (fp *FileProcessor) SetSinkFactory(factory SinkFactory) {
+	if factory != nil {
+		fp.newSink = factory
+	}
+}
 
-	if !strings.Contains(fileName, ".ibt") {
-		return nil, fmt.Errorf("not an IBT file: %s\nAction: Ensure file has .ibt extension", fileName)
+// AddChannels decodes extra IBT channels on top of the manifest
+func (fp *FileProcessor) AddChannels(names ...string) {
+	for _, name := range names {
+		if !slices.Contains(fp.channels, name) {
+			fp.channels = append(fp.channels, name)
+		}
 	}
+}
 
-	sessionTime, err := fp.parseFileName(fileName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse time from filename %s: %w\nAction: Ensure filename contains date pattern YYYY-MM-DD HH-MM-SS", fileName, err)
+func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string, fileEntry os.DirEntry) (*ProcessResult, error) {
+	fileName := fileEntry.Name()
+
+	if !IsIBTFile(fileName) {
+		return nil, newFileError(KindBadFile, "not an IBT file: %s\nAction: Ensure file has .ibt extension", fileName)
 	}
 
 	file := filepath.Join(telemetryFolder, fileName)
 
 	stubs, err := ibt.ParseStubs(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse stubs for %v: %w\nAction: File may be corrupted or incomplete - verify file integrity", file, err)
+		return nil, stubsError(file, err)
 	}
 
 	if len(stubs) == 0 {
-		return nil, fmt.Errorf("no telemetry data found in IBT file: %s\nAction: File is empty or contains no valid telemetry data", fileName)
+		return nil, newFileError(KindBadFile, "no telemetry data found in IBT file: %s\nAction: File is empty or contains no valid telemetry data", fileName)
 	}
 
 	groups := stubs.Group()
@@ -82,6 +134,8 @@ func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string
 
 	totalRecords := 0
 	totalBatches := 0
+	totalLaps := 0
+	var sessions []string
 
 	processors := make([]ibt.Processor, 0, len(groups))
 
@@ -89,6 +143,7 @@ func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string
 	var allMessagingMetrics *messaging.PublishMetrics
 	var firstSessionID string
 	var firstTrackName string
+	var fileHash string
 
 	for groupNumber, group := range groups {
 		select {
@@ -111,7 +166,7 @@ func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string
 
 		groupHeaders := group[0].Headers()
 		groupWeekendInfo := groupHeaders.SessionInfo.WeekendInfo
-		groupSessionID := strconv.Itoa(groupWeekendInfo.SubSessionID)
+		groupSessionID := groupSubSessionID(groupHeaders)
 
 		// Track first session's info for result
 		if groupNumber == 0 {
@@ -119,50 +174,107 @@ func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string
 			firstTrackName = groupWeekendInfo.TrackDisplayName
 		}
 
-		// Create PubSub for this specific group
-		pubSub := messaging.NewPubSub(
-			groupSessionID,
-			sessionTime,
-			fp.config,
-			fp.pool,
-			fp.workerID,
-		)
+		// One start per group keeps TickTime consistent across its stubs
+		sessionTime, err := fp.sessionStart(group, fileName)
+		if err != nil {
+			return nil, newFileError(KindBadFile, "failed to find session start for group %d: %w\nAction: Restore the original iRacing file name or set INGEST_TIMEZONE to the zone it was recorded in", groupNumber, err)
+		}
+
+		// Create the output sink for this specific group
+		out, err := fp.newSink(groupSessionID, groupWeekendInfo.TrackDisplayName, sessionTime)
+		if err != nil {
+			return nil, classify(KindBroker, err)
+		}
+
+		// Key batch IDs on the file contents so a re-ingest repeats them
+		if keyed, ok := out.(batchKeyer); ok {
+			if fileHash == "" {
+				if fileHash, err = ledger.HashFile(file); err != nil {
+					out.Close()
+					return nil, classify(ioKind(err, KindBadFile), err)
+				}
+			}
+			keyed.SetBatchKey(fmt.Sprintf("%s_%d", fileHash[:16], groupNumber))
+		}
+
+		// Session metadata goes out once per group, ahead of the ticks
+		if err := out.ExecSession(buildSessionMetadata(groupHeaders, groupSessionID, sessionTime)); err != nil {
+			out.Close()
+			return nil, classify(KindBroker, fmt.Errorf("failed to send session metadata for group %d: %w", groupNumber, err))
+		}
 
 		// Create telemetry processor with the correct SubSessionID
-		processor := NewProcessor(pubSub, groupNumber, fp.config, fp.workerID, groupSessionID)
-		processor.SetProgressCallback(fp.progressCallback, fileName)
-		processors = append(processors, processor)
+		loader := NewProcessor(out, groupNumber, fp.config, fp.workerID, groupSessionID, sessionTime)
+		loader.SetProgressCallback(fp.progressCallback, fileName)
+
+		// Manifest channels and CarIdx arrays are read over the map
+		// pipeline alongside the struct one, ahead of the loader
+		carStride := 0
+		if fp.config.CarIdx {
+			carStride = max(fp.config.CarIdxStride, 1)
+		}
 
-		if err := ibt.Process(ctx, group, processor); err != nil {
+		groupProcessors := []ibt.Processor{loader}
+		if len(fp.channels) > 0 || carStride > 0 {
+			groupProcessors = []ibt.Processor{newChannelReader(loader, fp.channels, carStride), loader}
+		}
+		processors = append(processors, loader)
+
+		if err := ibt.Process(ctx, group, groupProcessors...); err != nil {
 			// Try to flush this processor before returning error
-			if flushErr := processor.FlushPendingData(); flushErr != nil {
+			if flushErr := loader.FlushPendingData(); flushErr != nil {
 				log.Printf("Failed to flush processor on error: %v", flushErr)
 			}
-			pubSub.Close()
-			return nil, err
+			out.Close()
+
+			switch {
+			case ctx.Err() != nil:
+				return nil, ctx.Err()
+			case loader.sinkError() != nil:
+				return nil, loader.sinkError()
+			default:
+				return nil, classify(ioKind(err, KindParse), err)
+			}
 		}
 
-		if err := processor.Close(); err != nil {
-			pubSub.Close()
-			return nil, fmt.Errorf("error closing processor for group %d: %w\nAction: Check RabbitMQ connectivity and disk space", groupNumber, err)
+		if err := loader.Close(); err != nil {
+			out.Close()
+			return nil, classify(KindBroker, fmt.Errorf("error closing processor for group %d: %w\nAction: Check the %s sink is reachable and there is free disk space", groupNumber, err, fp.config.Sink))
+		}
+
+		// Close the sink for this group, its final batch goes out on close.
+		// A batch lost here means the file was not fully sent.
+		if err := out.Close(); err != nil {
+			return nil, classify(KindBroker, fmt.Errorf("failed to close sink for group %d: %w", groupNumber, err))
+		}
+
+		// Collect metrics from this group's sink
+		metrics := out.GetMetrics()
+		counts := loader.GetMetrics().(ProcessorMetrics)
+		totalRecords += counts.TotalProcessed
+		totalBatches += metrics.TotalBatches
+		totalLaps += counts.Laps
+		if !slices.Contains(sessions, groupSessionID) {
+			sessions = append(sessions, groupSessionID)
 		}
 
-		// Collect metrics from this group's PubSub
-		metrics := pubSub.GetMetrics()
 		if allMessagingMetrics == nil {
 			allMessagingMetrics = &metrics
 		} else {
 			// Accumulate metrics across groups
 			allMessagingMetrics.TotalBatches += metrics.TotalBatches
+			allMessagingMetrics.SentBatches += metrics.SentBatches
 			allMessagingMetrics.TotalRecords += metrics.TotalRecords
 			allMessagingMetrics.TotalBytes += metrics.TotalBytes
 			allMessagingMetrics.FailedBatches += metrics.FailedBatches
 			allMessagingMetrics.PersistedBatches += metrics.PersistedBatches
-		}
-
-		// Close PubSub for this group
-		if err := pubSub.Close(); err != nil {
-			log.Printf("Failed to close PubSub for group %d: %v", groupNumber, err)
+			allMessagingMetrics.CircuitBreakerTrips += metrics.CircuitBreakerTrips
+			allMessagingMetrics.CircuitBreakerOpen = metrics.CircuitBreakerOpen
+			allMessagingMetrics.CircuitBreakerState = metrics.CircuitBreakerState
+			allMessagingMetrics.MemoryPressureEvents += metrics.MemoryPressureEvents
+			allMessagingMetrics.BacklogEvents += metrics.BacklogEvents
+			allMessagingMetrics.AdaptiveBatchSize = metrics.AdaptiveBatchSize
+			allMessagingMetrics.FlushInterval = metrics.FlushInterval
 		}
 	}
 
@@ -177,6 +289,8 @@ func (fp *FileProcessor) ProcessFile(ctx context.Context, telemetryFolder string
 		SessionID:        firstSessionID,
 		TrackName:        firstTrackName,
 		MessagingMetrics: allMessagingMetrics,
+		Sessions:         sessions,
+		Laps:             totalLaps,
 	}, nil
 }
 
@@ -186,30 +300,6 @@ func (fp *FileProcessor) FlushPendingData() error {
 }
 
 func (fp *FileProcessor) Close() error {
-	// No-op: PubSub instances are closed per-group
+	// No-op: sinks are closed per-group
 	return nil
 }
-
-func (fp *FileProcessor) parseFileName(fileName string) (time.Time, error) {
-	regex := regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}-\d{2}-\d{2}`)
-
-	match := regex.FindString(fileName)
-	if match == "" {
-		return time.Time{}, fmt.Errorf("no date pattern found in filename: %s\nAction: Filename must contain YYYY-MM-DD HH-MM-SS pattern", fileName)
-	}
-
-	parts := strings.Split(match, " ")
-	if len(parts) != 2 {
-		return time.Time{}, fmt.Errorf("invalid date format: %s\nAction: Date pattern must be YYYY-MM-DD HH-MM-SS", match)
-	}
-
-	timeStr := strings.ReplaceAll(parts[1], "-", ":")
-	rfc3339Str := parts[0] + "T" + timeStr + "Z"
-
-	parsedTime, err := time.Parse(time.RFC3339, rfc3339Str)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to parse time %s: %w\nAction: Verify date format is valid YYYY-MM-DD HH-MM-SS", rfc3339Str, err)
-	}
-
-	return parsedTime, nil
-}
@@ -0,0 +1,118 @@
+package processing
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IsIBTFile matches IBT files by extension, so backups like .ibt.bak and
+// files that only mention .ibt in their name are left alone
+func IsIBTFile(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".ibt")
+}
+
+// Filter picks which IBT files a run sends. Empty fields match everything,
+// text fields match case insensitive substrings.
+type Filter struct {
+	// Session start, From inclusive and To exclusive
+	From time.Time
+	To   time.Time
+
+	Tracks []string
+	Cars   []string
+
+	// Any session in the file of one of these types, e.g. race or qualify
+	SessionTypes []string
+}
+
+// Empty reports whether the filter lets every file through
+func (f Filter) Empty() bool {
+	return f.From.IsZero() && f.To.IsZero() && len(f.Tracks) == 0 && len(f.Cars) == 0 && len(f.SessionTypes) == 0
+}
+
+// Match checks a file's headers, returning why it was left out if it was
+func (f Filter) Match(info *FileInfo) (bool, string) {
+	if !f.From.IsZero() || !f.To.IsZero() {
+		switch {
+		case info.SessionTime.IsZero():
+			return false, "no session date"
+		case !f.From.IsZero() && info.SessionTime.Before(f.From):
+			return false, "before " + f.From.Format(time.RFC3339)
+		case !f.To.IsZero() && !info.SessionTime.Before(f.To):
+			return false, "after " + f.To.Format(time.RFC3339)
+		}
+	}
+
+	if len(f.Tracks) > 0 && !containsAny(f.Tracks, info.Track, info.TrackConfig) {
+		return false, "track " + info.Track
+	}
+	if len(f.Cars) > 0 && !containsAny(f.Cars, info.Car) {
+		return false, "car " + info.Car
+	}
+
+	if len(f.SessionTypes) > 0 {
+		types := make([]string, len(info.Sessions))
+		for i, sess := range info.Sessions {
+			types[i] = sess.Type
+		}
+		if !containsAny(f.SessionTypes, types...) {
+			return false, "session types " + strings.Join(types, ", ")
+		}
+	}
+
+	return true, ""
+}
+
+func containsAny(wants []string, values ...string) bool {
+	for _, want := range wants {
+		want = strings.ToLower(strings.TrimSpace(want))
+		for _, value := range values {
+			if want != "" && strings.Contains(strings.ToLower(value), want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseDateRange reads --from and --to as YYYY-MM-DD in INGEST_TIMEZONE or
+// RFC3339. A date on its own for to includes the whole of that day.
+func ParseDateRange(from, to, timezone string) (time.Time, time.Time, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown timezone %q: %w\nAction: Set INGEST_TIMEZONE to Local, UTC or an IANA zone like Europe/London", timezone, err)
+	}
+
+	parse := func(flag, value string, endOfDay bool) (time.Time, error) {
+		if value == "" {
+			return time.Time{}, nil
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		day, err := time.ParseInLocation(time.DateOnly, value, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --%s %q\nAction: Use a date like 2024-05-01 or a time like 2024-05-01T19:30:00Z", flag, value)
+		}
+		if endOfDay {
+			day = day.AddDate(0, 0, 1)
+		}
+		return day, nil
+	}
+
+	start, err := parse("from", from, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := parse("to", to, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from %s is not before --to %s\nAction: Swap the dates", from, to)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,126 @@
+package processing
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsIBTFile(t *testing.T) {
+	testCases := []struct {
+		name string
+		file string
+		want bool
+	}{
+		{"Lower", "mx5 spa 2026-03-01 10-00-00.ibt", true},
+		{"Upper", "SESSION.IBT", true},
+		{"Backup", "session.ibt.bak", false},
+		{"MentionsIBT", "session.ibt.notes.txt", false},
+		{"NoExtension", "ibt", false},
+		{"Zip", "sessions.zip", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsIBTFile(tc.file); got != tc.want {
+				t.Errorf("IsIBTFile(%q) = %v, want %v", tc.file, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	sessionTime := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	info := &FileInfo{
+		SessionTime: sessionTime,
+		Track:       "Circuit de Spa-Francorchamps",
+		TrackConfig: "Grand Prix Pits",
+		Car:         "Mazda MX-5 Cup",
+		Sessions:    []SessionInfo{{Type: "Practice"}, {Type: "Race"}},
+	}
+
+	testCases := []struct {
+		name       string
+		filter     Filter
+		info       *FileInfo
+		want       bool
+		wantReason string
+	}{
+		{"Empty", Filter{}, info, true, ""},
+		{"FromInclusive", Filter{From: sessionTime}, info, true, ""},
+		{"Before", Filter{From: sessionTime.Add(time.Hour)}, info, false, "before"},
+		{"ToExclusive", Filter{To: sessionTime}, info, false, "after"},
+		{"InRange", Filter{From: sessionTime.Add(-time.Hour), To: sessionTime.Add(time.Hour)}, info, true, ""},
+		{"NoSessionDate", Filter{From: sessionTime}, &FileInfo{}, false, "no session date"},
+		{"TrackCaseInsensitive", Filter{Tracks: []string{"SPA"}}, info, true, ""},
+		{"TrackConfig", Filter{Tracks: []string{"grand prix"}}, info, true, ""},
+		{"OtherTrack", Filter{Tracks: []string{"monza"}}, info, false, "track Circuit de Spa-Francorchamps"},
+		{"AnyTrack", Filter{Tracks: []string{"monza", " spa "}}, info, true, ""},
+		{"BlankTrack", Filter{Tracks: []string{""}}, info, false, "track"},
+		{"Car", Filter{Cars: []string{"mx-5"}}, info, true, ""},
+		{"OtherCar", Filter{Cars: []string{"gt3"}}, info, false, "car Mazda MX-5 Cup"},
+		{"SessionType", Filter{SessionTypes: []string{"race"}}, info, true, ""},
+		{"OtherSessionType", Filter{SessionTypes: []string{"qualify"}}, info, false, "session types Practice, Race"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, reason := tc.filter.Match(tc.info)
+			if got != tc.want {
+				t.Errorf("Match = %v (%s), want %v", got, reason, tc.want)
+			}
+			if !strings.HasPrefix(reason, tc.wantReason) {
+				t.Errorf("reason = %q, want it to start with %q", reason, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		timezone string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  string
+	}{
+		{"Empty", "", "", "UTC", time.Time{}, time.Time{}, ""},
+		{"Dates", "2026-03-01", "2026-03-02", "UTC", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), ""},
+		{"SameDay", "2026-03-01", "2026-03-01", "UTC", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), ""},
+		{"Timezone", "2026-07-01", "", "Europe/London", time.Date(2026, 7, 1, 0, 0, 0, 0, london), time.Time{}, ""},
+		{"RFC3339", "2026-03-01T19:30:00Z", "2026-03-01T21:00:00Z", "UTC", time.Date(2026, 3, 1, 19, 30, 0, 0, time.UTC), time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC), ""},
+		{"OnlyTo", "", "2026-03-01", "UTC", time.Time{}, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), ""},
+		{"BadDate", "01/03/2026", "", "UTC", time.Time{}, time.Time{}, "invalid --from"},
+		{"BadTo", "", "tomorrow", "UTC", time.Time{}, time.Time{}, "invalid --to"},
+		{"Reversed", "2026-03-02T00:00:00Z", "2026-03-01T00:00:00Z", "UTC", time.Time{}, time.Time{}, "is not before"},
+		{"UnknownTimezone", "2026-03-01", "", "Mars/Olympus", time.Time{}, time.Time{}, "unknown timezone"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := ParseDateRange(tc.from, tc.to, tc.timezone)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("ParseDateRange error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDateRange failed: %v", err)
+			}
+			if !from.Equal(tc.wantFrom) {
+				t.Errorf("from = %v, want %v", from, tc.wantFrom)
+			}
+			if !to.Equal(tc.wantTo) {
+				t.Errorf("to = %v, want %v", to, tc.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package processing
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/OJPARKINSON/ibt"
+	"github.com/OJPARKINSON/ibt/headers"
+)
+
+// IBT variable types from the irsdk var header
+var varTypes = map[int]string{
+	0: "char",
+	1: "bool",
+	2: "int",
+	3: "bitfield",
+	4: "float",
+	5: "double",
+}
+
+// FileInfo describes an IBT file without sending anything
+type FileInfo struct {
+	File        string    `json:"file"`
+	SessionTime time.Time `json:"session_time,omitzero"`
+
+	// SubSessionID of the first group, what ProcessResult.SessionID reports
+	SessionID string `json:"session_id"`
+
+	Track       string        `json:"track"`
+	TrackConfig string        `json:"track_config"`
+	Car         string        `json:"car"`
+	Driver      string        `json:"driver"`
+	EventType   string        `json:"event_type"`
+	Sessions    []SessionInfo `json:"sessions"`
+	Groups      []GroupInfo   `json:"groups"`
+	Variables   []VarInfo     `json:"variables"`
+}
+
+type SessionInfo struct {
+	Num  int    `json:"num"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Laps string `json:"laps"`
+	Time string `json:"time"`
+}
+
+// GroupInfo is one stubs.Group() entry, processed with its own sink
+type GroupInfo struct {
+	Index     int       `json:"index"`
+	SessionID string    `json:"session_id"`
+	Stubs     int       `json:"stubs"`
+	Records   int       `json:"records"`
+	Laps      int       `json:"laps"`
+	Start     float64   `json:"start"`
+	End       float64   `json:"end"`
+	StartDate time.Time `json:"start_date,omitzero"`
+}
+
+type VarInfo struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+	Unit  string `json:"unit"`
+	Desc  string `json:"desc"`
+}
+
+// Inspect reads the headers of an IBT file and its stub groups the same way
+// ProcessFile does, without decoding any ticks.
+func (fp *FileProcessor) Inspect(path string) (*FileInfo, error) {
+	info := &FileInfo{File: filepath.Base(path)}
+
+	stubs, err := ibt.ParseStubs(path)
+	if err != nil {
+		return nil, stubsError(path, err)
+	}
+
+	if len(stubs) == 0 {
+		return nil, newFileError(KindBadFile, "no telemetry data found in IBT file: %s\nAction: File is empty or contains no valid telemetry data", info.File)
+	}
+
+	groups := stubs.Group()
+	defer ibt.CloseAllStubs(groups)
+
+	for index, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		groupInfo := GroupInfo{
+			Index:     index,
+			SessionID: groupSubSessionID(group[0].Headers()),
+			Stubs:     len(group),
+		}
+
+		for i, stub := range group {
+			disk := stub.Headers().DiskHeader
+			if disk == nil {
+				continue
+			}
+
+			groupInfo.Records += disk.RecordCount
+			groupInfo.Laps += disk.LapCount
+			if i == 0 || disk.StartTime < groupInfo.Start {
+				groupInfo.Start = disk.StartTime
+			}
+			groupInfo.End = max(groupInfo.End, disk.EndTime)
+			if i == 0 && disk.StartDate > 0 {
+				groupInfo.StartDate = time.Unix(disk.StartDate, 0).UTC()
+			}
+		}
+
+		if len(info.Groups) == 0 {
+			info.SessionID = groupInfo.SessionID
+
+			// Renamed files without a start date in the headers have none
+			if sessionTime, err := fp.sessionStart(group, info.File); err == nil {
+				info.SessionTime = sessionTime
+			}
+		}
+		info.Groups = append(info.Groups, groupInfo)
+	}
+
+	header := stubs[0].Headers()
+	if session := header.SessionInfo; session != nil {
+		info.Track = session.WeekendInfo.TrackDisplayName
+		info.TrackConfig = session.WeekendInfo.TrackConfigName
+		info.EventType = session.WeekendInfo.EventType
+
+		for _, driver := range session.DriverInfo.Drivers {
+			if driver.CarIdx == session.DriverInfo.DriverCarIdx {
+				info.Car = driver.CarScreenName
+				info.Driver = driver.UserName
+			}
+		}
+
+		for _, sess := range session.SessionInfo.Sessions {
+			info.Sessions = append(info.Sessions, SessionInfo{
+				Num:  sess.SessionNum,
+				Type: sess.SessionType,
+				Name: sess.SessionName,
+				Laps: sess.SessionLaps,
+				Time: sess.SessionTime,
+			})
+		}
+	}
+
+	for name, v := range header.VarHeader {
+		varType, ok := varTypes[v.Rtype]
+		if !ok {
+			varType = strconv.Itoa(v.Rtype)
+		}
+		info.Variables = append(info.Variables, VarInfo{
+			Name:  name,
+			Type:  varType,
+			Count: v.Count,
+			Unit:  v.Unit,
+			Desc:  v.Desc,
+		})
+	}
+	sort.Slice(info.Variables, func(i, j int) bool {
+		return info.Variables[i].Name < info.Variables[j].Name
+	})
+
+	return info, nil
+}
+
+// groupSubSessionID is the SubSessionID a group's ticks are published under
+func groupSubSessionID(header *headers.Header) string {
+	if header == nil || header.SessionInfo == nil {
+		return ""
+	}
+	return strconv.Itoa(header.SessionInfo.WeekendInfo.SubSessionID)
+}
@@ -0,0 +1,123 @@
+package processing
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt/headers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// buildSessionMetadata collects the parts of the session YAML the ticks do
+// not carry: weekend, weather, the driver roster and the player's setup.
+func buildSessionMetadata(header *headers.Header, sessionID string, sessionTime time.Time) *messaging.SessionMetadata {
+	if header == nil || header.SessionInfo == nil {
+		return nil
+	}
+
+	session := header.SessionInfo
+	weekend := session.WeekendInfo
+
+	meta := &messaging.SessionMetadata{
+		SessionId:    sessionID,
+		SessionStart: timestamppb.New(sessionTime.UTC()),
+		TrackName:    weekend.TrackDisplayName,
+		TrackId:      strconv.Itoa(weekend.TrackID),
+		TrackConfig:  weekend.TrackConfigName,
+		TrackCity:    weekend.TrackCity,
+		TrackCountry: weekend.TrackCountry,
+		TrackLength:  weekend.TrackLength,
+		SeriesId:     strconv.Itoa(weekend.SeriesID),
+		SeasonId:     strconv.Itoa(weekend.SeasonID),
+		EventType:    weekend.EventType,
+		Category:     weekend.Category,
+		Weather: &messaging.SessionMetadata_Weather{
+			Type:             weekend.TrackWeatherType,
+			Skies:            weekend.TrackSkies,
+			TrackTemp:        weekend.TrackSurfaceTemp,
+			AirTemp:          weekend.TrackAirTemp,
+			AirPressure:      weekend.TrackAirPressure,
+			WindSpeed:        weekend.TrackWindVel,
+			WindDir:          weekend.TrackWindDir,
+			RelativeHumidity: weekend.TrackRelativeHumidity,
+			FogLevel:         weekend.TrackFogLevel,
+			TimeOfDay:        weekend.WeekendOptions.TimeOfDay,
+			Date:             weekend.WeekendOptions.Date,
+		},
+		PlayerCarIdx: uint32(session.DriverInfo.DriverCarIdx),
+		SetupName:    session.DriverInfo.DriverSetupName,
+	}
+
+	for _, sess := range session.SessionInfo.Sessions {
+		meta.Sessions = append(meta.Sessions, &messaging.SessionMetadata_Session{
+			SessionNum:  uint32(sess.SessionNum),
+			SessionType: sess.SessionType,
+			SessionName: sess.SessionName,
+			SessionLaps: sess.SessionLaps,
+			SessionTime: sess.SessionTime,
+		})
+	}
+
+	for _, driver := range session.DriverInfo.Drivers {
+		meta.Drivers = append(meta.Drivers, &messaging.SessionMetadata_Driver{
+			CarIdx:      uint32(driver.CarIdx),
+			UserName:    driver.UserName,
+			UserId:      uint32(driver.UserID),
+			TeamName:    driver.TeamName,
+			CarNumber:   driver.CarNumber,
+			CarName:     driver.CarScreenName,
+			CarClass:    driver.CarClassShortName,
+			Irating:     int32(driver.IRating),
+			License:     driver.LicString,
+			IsAi:        driver.CarIsAI != 0,
+			IsPaceCar:   driver.CarIsPaceCar != 0,
+			IsSpectator: driver.IsSpectator != 0,
+		})
+
+		if driver.CarIdx == session.DriverInfo.DriverCarIdx {
+			meta.CarName = driver.CarScreenName
+			meta.CarPath = driver.CarPath
+		}
+	}
+
+	if len(session.CarSetup) > 0 {
+		setup, err := json.Marshal(jsonSafe(session.CarSetup))
+		if err != nil {
+			log.Printf("Session %s: could not encode car setup, sending metadata without it: %v", sessionID, err)
+		} else {
+			meta.CarSetup = string(setup)
+		}
+	}
+
+	return meta
+}
+
+// jsonSafe converts the map[interface{}]interface{} nodes a YAML decoder can
+// produce into string keyed maps so the setup can be JSON encoded.
+func jsonSafe(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = jsonSafe(item)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = jsonSafe(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = jsonSafe(item)
+		}
+		return out
+	}
+	return value
+}
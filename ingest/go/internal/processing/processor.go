@@ -8,15 +8,18 @@ import (
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
 	"github.com/OJPARKINSON/ibt"
 	"github.com/OJPARKINSON/ibt/headers"
 )
 
-// loaderProcessor processes telemetry data and sends it to RabbitMQ.
+// loaderProcessor processes telemetry data and sends it to the configured sink.
 // It uses struct-based processing for optimal performance.
 type loaderProcessor struct {
-	pubSub         *messaging.PubSub
+	out            sink.Sink
 	cache          []*ibt.TelemetryTick
+	channelCache   []map[string]float64
+	carCache       []*messaging.CarTelemetry
 	groupNumber    int
 	thresholdBytes int
 	workerID       int
@@ -25,7 +28,8 @@ type loaderProcessor struct {
 
 	session *headers.Session
 
-	subSessionID   string // The unique SubSessionID for this group
+	subSessionID   string    // The unique SubSessionID for this group
+	sessionStart   time.Time // Wall clock time of SessionTime 0
 	sessionMap     map[int]sessionInfo
 	trackName      string
 	trackID        int
@@ -37,11 +41,27 @@ type loaderProcessor struct {
 	totalProcessed int
 	totalBatches   int
 
+	// First error the sink returned, ibt.Process may not wrap it
+	sinkErr error
+
+	// Manifest channels channelReader read for the tick about to arrive
+	pendingChannels    map[string]float64
+	pendingSessionTime float64
+
+	// Distinct laps seen, only looked up when the lap changes
+	laps    map[lapKey]struct{}
+	lastLap lapKey
+
 	// Progress tracking
 	progressCallback ProgressCallback
 	currentFile      string
 }
 
+type lapKey struct {
+	sessionNum int32
+	lap        int32
+}
+
 // sessionInfo holds session metadata
 type sessionInfo struct {
 	sessionNum  int
@@ -53,6 +73,7 @@ type sessionInfo struct {
 type ProcessorMetrics struct {
 	TotalProcessed       int
 	TotalBatches         int
+	Laps                 int
 	ProcessingTime       time.Duration
 	MaxBatchSize         int
 	ProcessingStarted    time.Time
@@ -61,16 +82,19 @@ type ProcessorMetrics struct {
 }
 
 // NewProcessor creates a new telemetry processor
-func NewProcessor(pubSub *messaging.PubSub, groupNumber int, config *config.Config, workerID int, subSessionID string) *loaderProcessor {
+func NewProcessor(out sink.Sink, groupNumber int, config *config.Config, workerID int, subSessionID string, sessionStart time.Time) *loaderProcessor {
 	return &loaderProcessor{
-		pubSub:           pubSub,
+		out:              out,
 		cache:            make([]*ibt.TelemetryTick, 0, config.BatchSizeRecords),
 		groupNumber:      groupNumber,
 		config:           config,
 		thresholdBytes:   config.BatchSizeBytes,
 		workerID:         workerID,
 		subSessionID:     subSessionID,
+		sessionStart:     sessionStart,
 		sessionMap:       make(map[int]sessionInfo),
+		laps:             make(map[lapKey]struct{}),
+		lastLap:          lapKey{-1, -1},
 		progressCallback: &NoOpProgressCallback{},
 		tickPool: &sync.Pool{
 			New: func() any {
@@ -93,6 +117,38 @@ func (l *loaderProcessor) Init(session *headers.Session) error {
 }
 
 func (l *loaderProcessor) ProcessStruct(tick *ibt.TelemetryTick, hasNext bool) error {
+	return l.processTick(tick, l.takeChannels(tick.SessionTime))
+}
+
+// setChannels holds the manifest channels read for the next tick
+func (l *loaderProcessor) setChannels(sessionTime float64, channels map[string]float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pendingChannels, l.pendingSessionTime = channels, sessionTime
+}
+
+// takeChannels returns the channels held for the tick at sessionTime. They
+// are dropped rather than attached to the wrong tick if the times differ.
+func (l *loaderProcessor) takeChannels(sessionTime float64) map[string]float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	channels := l.pendingChannels
+	l.pendingChannels = nil
+	if channels == nil || l.pendingSessionTime != sessionTime {
+		return nil
+	}
+	return channels
+}
+
+// tickTime places a SessionTime on the group's wall clock
+func (l *loaderProcessor) tickTime(sessionTime float64) time.Time {
+	return l.sessionStart.Add(time.Duration(sessionTime * float64(time.Second))).UTC()
+}
+
+// processTick stamps the session details onto the tick and queues it, along
+// with any manifest channels read for it, for the next batch.
+func (l *loaderProcessor) processTick(tick *ibt.TelemetryTick, channels map[string]float64) error {
 	if !l.sessionInfoSet && l.session != nil && len(l.session.SessionInfo.Sessions) > 0 {
 		for _, sess := range l.session.SessionInfo.Sessions {
 			l.sessionMap[sess.SessionNum] = sessionInfo{
@@ -113,6 +169,7 @@ func (l *loaderProcessor) ProcessStruct(tick *ibt.TelemetryTick, hasNext bool) e
 
 	// Use the SubSessionID from the header instead of SessionNum
 	tick.SessionID = l.subSessionID
+	tick.TickTime = l.tickTime(tick.SessionTime)
 
 	if sessionInfo, exists := l.sessionMap[int(tick.SessionNum)]; exists {
 		tick.SessionType = sessionInfo.sessionType
@@ -135,23 +192,42 @@ func (l *loaderProcessor) ProcessStruct(tick *ibt.TelemetryTick, hasNext bool) e
 	tickCopy := l.tickPool.Get().(*ibt.TelemetryTick)
 	*tickCopy = *tick
 	l.cache = append(l.cache, tickCopy)
+	// Sinks pair channels with ticks by index, so a tick without channels
+	// still takes its slot
+	l.channelCache = append(l.channelCache, channels)
 	l.totalProcessed++
 
+	if lap := (lapKey{tick.SessionNum, tick.LapID}); lap != l.lastLap {
+		l.laps[lap] = struct{}{}
+		l.lastLap = lap
+	}
+
 	return nil
 }
 
+// processCars queues per-car records, they are sent with the next batch
+func (l *loaderProcessor) processCars(cars []*messaging.CarTelemetry) {
+	l.mu.Lock()
+	l.carCache = append(l.carCache, cars...)
+	l.mu.Unlock()
+}
+
 func (l *loaderProcessor) loadBatch() error {
+	if len(l.carCache) > 0 {
+		if err := l.out.ExecCars(l.carCache); err != nil {
+			return l.sinkFailed(err)
+		}
+		l.carCache = nil
+	}
+
 	if len(l.cache) == 0 {
 		return nil
 	}
 
 	batchSize := len(l.cache)
 
-	if !l.config.DisableRabbitMQ {
-		err := l.pubSub.ExecStructs(l.cache)
-		if err != nil {
-			return err
-		}
+	if err := l.out.ExecStructs(l.cache, l.channelCache); err != nil {
+		return l.sinkFailed(err)
 	}
 
 	for _, tick := range l.cache {
@@ -159,6 +235,7 @@ func (l *loaderProcessor) loadBatch() error {
 	}
 
 	l.cache = l.cache[:0]
+	l.channelCache = l.channelCache[:0]
 	l.totalBatches++
 
 	// Report progress after batch is sent
@@ -169,11 +246,27 @@ func (l *loaderProcessor) loadBatch() error {
 	return nil
 }
 
+// sinkFailed remembers a sink error, called with mu held
+func (l *loaderProcessor) sinkFailed(err error) error {
+	err = classify(KindBroker, err)
+	if l.sinkErr == nil {
+		l.sinkErr = err
+	}
+	return err
+}
+
+// sinkError is the first error the sink returned, if any
+func (l *loaderProcessor) sinkError() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sinkErr
+}
+
 func (l *loaderProcessor) Close() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if len(l.cache) > 0 {
+	if len(l.cache) > 0 || len(l.carCache) > 0 {
 		return l.loadBatch()
 	}
 
@@ -245,7 +338,7 @@ func (l *loaderProcessor) FlushPendingData() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if len(l.cache) > 0 {
+	if len(l.cache) > 0 || len(l.carCache) > 0 {
 		log.Printf("Worker %d: Flushing %d pending struct records",
 			l.workerID, len(l.cache))
 		return l.loadBatch()
@@ -254,5 +347,16 @@ func (l *loaderProcessor) FlushPendingData() error {
 }
 
 func (l *loaderProcessor) GetMetrics() any {
-	return nil // Return nil for now - can be extended later if needed
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	publish := l.out.GetMetrics()
+	return ProcessorMetrics{
+		TotalProcessed:       l.totalProcessed,
+		TotalBatches:         l.totalBatches,
+		Laps:                 len(l.laps),
+		MaxBatchSize:         l.config.BatchSizeRecords,
+		MemoryPressureEvents: publish.MemoryPressureEvents,
+		AdaptiveBatchSize:    publish.AdaptiveBatchSize,
+	}
 }
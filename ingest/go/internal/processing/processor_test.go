@@ -0,0 +1,77 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+)
+
+// recordingSink keeps the channels each tick was sent with, by SessionTime
+type recordingSink struct {
+	ticks    int
+	channels map[float64]map[string]float64
+}
+
+func (s *recordingSink) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
+	for i, tick := range ticks {
+		if i < len(channels) {
+			s.channels[tick.SessionTime] = channels[i]
+		}
+	}
+	s.ticks += len(ticks)
+	return nil
+}
+
+func (s *recordingSink) ExecCars(cars []*messaging.CarTelemetry) error     { return nil }
+func (s *recordingSink) ExecSession(meta *messaging.SessionMetadata) error { return nil }
+func (s *recordingSink) Close() error                                      { return nil }
+func (s *recordingSink) GetMetrics() messaging.PublishMetrics              { return messaging.PublishMetrics{} }
+
+func TestProcessTickKeepsChannelsWithTheirTicks(t *testing.T) {
+	// Channels read for each tick, and the SessionTime they were read at.
+	// A mismatched time makes takeChannels drop the map.
+	testCases := []struct {
+		name        string
+		readAt      float64
+		channels    map[string]float64
+		sessionTime float64
+		want        map[string]float64
+	}{
+		{"First", 1, map[string]float64{"Speed": 1}, 1, map[string]float64{"Speed": 1}},
+		{"Dropped", 1.5, map[string]float64{"Speed": 2}, 2, nil},
+		{"NoneRead", 0, nil, 3, nil},
+		{"AfterDropped", 4, map[string]float64{"Speed": 4}, 4, map[string]float64{"Speed": 4}},
+		{"Last", 5, map[string]float64{"Speed": 5}, 5, map[string]float64{"Speed": 5}},
+	}
+
+	out := &recordingSink{channels: make(map[float64]map[string]float64)}
+	cfg := &config.Config{BatchSizeRecords: 100, BatchSizeBytes: 1 << 20}
+	l := NewProcessor(out, 0, cfg, 0, "123", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	for _, tc := range testCases {
+		if tc.channels != nil {
+			l.setChannels(tc.readAt, tc.channels)
+		}
+		if err := l.ProcessStruct(&ibt.TelemetryTick{SessionTime: tc.sessionTime}, true); err != nil {
+			t.Fatalf("ProcessStruct %s failed: %v", tc.name, err)
+		}
+	}
+	if err := l.FlushPendingData(); err != nil {
+		t.Fatalf("FlushPendingData failed: %v", err)
+	}
+	if out.ticks != len(testCases) {
+		t.Fatalf("sent %d ticks, want %d", out.ticks, len(testCases))
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := out.channels[tc.sessionTime]
+			if (got == nil) != (tc.want == nil) || got["Speed"] != tc.want["Speed"] {
+				t.Errorf("tick at %v sent with channels %v, want %v", tc.sessionTime, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package processing
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/OJPARKINSON/ibt"
+)
+
+var fileNameDate = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}-\d{2}-\d{2}`)
+
+// sessionStart is the wall clock time of SessionTime 0 for a group, so every
+// stub in the group maps SessionTime onto the same TickTime. The disk header
+// start date is used when there is one, otherwise the date in the file name
+// read in the configured timezone. INGEST_LEGACY_TICK_TIME always reads the
+// file name as UTC.
+func (fp *FileProcessor) sessionStart(group ibt.StubGroup, fileName string) (time.Time, error) {
+	location := fp.location
+	if fp.config.LegacyTickTime {
+		location = time.UTC
+	} else if start, ok := headerStart(group); ok {
+		return start, nil
+	}
+
+	start, err := fp.parseFileName(fileName, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("no session start in headers or filename of %s: %w", fileName, err)
+	}
+	return start, nil
+}
+
+// headerStart takes the earliest stub with a start date. The disk header
+// records when the recording started and the SessionTime it started at.
+func headerStart(group ibt.StubGroup) (time.Time, bool) {
+	var start time.Time
+	found := false
+	earliest := 0.0
+
+	for _, stub := range group {
+		disk := stub.Headers().DiskHeader
+		if disk == nil || disk.StartDate <= 0 {
+			continue
+		}
+		if found && disk.StartTime >= earliest {
+			continue
+		}
+
+		recording := time.Unix(disk.StartDate, 0)
+		start = recording.Add(-time.Duration(disk.StartTime * float64(time.Second))).UTC()
+		earliest = disk.StartTime
+		found = true
+	}
+
+	return start, found
+}
+
+// parseFileName reads the local date iRacing puts in file names,
+// "car_track 2024-05-01 19-30-00.ibt"
+func (fp *FileProcessor) parseFileName(fileName string, location *time.Location) (time.Time, error) {
+	match := fileNameDate.FindString(fileName)
+	if match == "" {
+		return time.Time{}, fmt.Errorf("no date pattern found in filename: %s\nAction: Filename must contain YYYY-MM-DD HH-MM-SS pattern", fileName)
+	}
+
+	parsedTime, err := time.ParseInLocation("2006-01-02 15-04-05", match, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse time %s: %w\nAction: Verify date format is valid YYYY-MM-DD HH-MM-SS", match, err)
+	}
+
+	return parsedTime.UTC(), nil
+}
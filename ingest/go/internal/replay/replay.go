@@ -0,0 +1,242 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
+	"github.com/OJPARKINSON/ibt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Recordings pause while the car sits in the garage, replay skips the wait
+const maxPause = 5 * time.Second
+
+// ParseSpeed reads 1x, 4x, 0.5x or max. Max returns 0, no pacing.
+func ParseSpeed(value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "max" {
+		return 0, nil
+	}
+
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid replay speed %q\nAction: Use a multiplier like 1x or 4x, or max", value)
+	}
+	return speed, nil
+}
+
+// flusher is a sink that holds records back until its batch fills,
+// messaging.PubSub does
+type flusher interface {
+	FlushBatch() error
+}
+
+// Pacer wraps a sink and releases the ticks it is given in small batches
+// at the rate they were recorded, scaled by speed. Each batch covers at
+// most window of SessionTime and is flushed on its own, so the receiver
+// sees the steady trickle of a live session instead of bulk batches.
+// Rows are moved onto the wall clock, the first tick at the time it is
+// released, and written under sessionID so they do not overwrite the
+// recorded session or an earlier replay.
+type Pacer struct {
+	ctx    context.Context
+	out    sink.Sink
+	speed  float64
+	window float64
+
+	// Wall clock time SessionTime anchor was released at
+	started time.Time
+	anchor  float64
+	last    float64
+
+	// Cars and the session metadata arrive ahead of the ticks and go out
+	// with the first batch that covers them
+	cars []*messaging.CarTelemetry
+	meta *messaging.SessionMetadata
+
+	sessionID string
+	shift     time.Duration
+	shifted   bool
+
+	batches int
+}
+
+// NewPacer paces out, speed 0 sends as fast as out accepts. An empty
+// sessionID keeps the recorded one.
+func NewPacer(ctx context.Context, out sink.Sink, speed float64, window time.Duration, sessionID string) *Pacer {
+	return &Pacer{
+		ctx:       ctx,
+		out:       out,
+		speed:     speed,
+		window:    window.Seconds(),
+		sessionID: sessionID,
+	}
+}
+
+// rebase moves a recorded time onto the wall clock, the first time seen
+// lands on now
+func (p *Pacer) rebase(t time.Time) time.Time {
+	if !p.shifted {
+		p.shift = time.Since(t)
+		p.shifted = true
+	}
+	return t.Add(p.shift)
+}
+
+func (p *Pacer) restamp(sessionID *string) {
+	if p.sessionID != "" {
+		*sessionID = p.sessionID
+	}
+}
+
+func (p *Pacer) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
+	for start := 0; start < len(ticks); {
+		end := start + 1
+		for end < len(ticks) &&
+			ticks[end].SessionTime >= ticks[start].SessionTime &&
+			ticks[end].SessionTime-ticks[start].SessionTime < p.window {
+			end++
+		}
+
+		if err := p.wait(ticks[start].SessionTime); err != nil {
+			return err
+		}
+
+		var batchChannels []map[string]float64
+		if start < len(channels) {
+			batchChannels = channels[start:min(end, len(channels))]
+		}
+		if err := p.send(ticks[start:end], batchChannels, ticks[end-1].SessionTime); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// send releases one batch of ticks and the cars recorded up to its end
+func (p *Pacer) send(ticks []*ibt.TelemetryTick, channels []map[string]float64, end float64) error {
+	for _, tick := range ticks {
+		tick.TickTime = p.rebase(tick.TickTime)
+		p.restamp(&tick.SessionID)
+	}
+
+	if p.meta != nil {
+		if err := p.sendSession(); err != nil {
+			return err
+		}
+	}
+
+	due := 0
+	for due < len(p.cars) && p.cars[due].SessionTime <= end {
+		due++
+	}
+	if due > 0 {
+		if err := p.sendCars(p.cars[:due]); err != nil {
+			return err
+		}
+		p.cars = p.cars[due:]
+	}
+
+	if err := p.out.ExecStructs(ticks, channels); err != nil {
+		return err
+	}
+	p.batches++
+
+	if f, ok := p.out.(flusher); ok {
+		return f.FlushBatch()
+	}
+	return nil
+}
+
+// wait sleeps until sessionTime is due. Time going backwards (a new
+// session) or a long pause starts the clock again from this tick.
+func (p *Pacer) wait(sessionTime float64) error {
+	if p.speed == 0 {
+		return p.ctx.Err()
+	}
+
+	now := time.Now()
+	if p.started.IsZero() || sessionTime < p.last || sessionTime-p.last > maxPause.Seconds() {
+		p.started = now
+		p.anchor = sessionTime
+	}
+	p.last = sessionTime
+
+	offset := time.Duration((sessionTime - p.anchor) / p.speed * float64(time.Second))
+	delay := p.started.Add(offset).Sub(now)
+	if delay <= 0 {
+		return p.ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *Pacer) sendCars(cars []*messaging.CarTelemetry) error {
+	for _, car := range cars {
+		if car.TickTime != nil {
+			car.TickTime = timestamppb.New(p.rebase(car.TickTime.AsTime()))
+		}
+		p.restamp(&car.SessionId)
+	}
+	return p.out.ExecCars(cars)
+}
+
+func (p *Pacer) sendSession() error {
+	if p.meta.SessionStart != nil {
+		p.meta.SessionStart = timestamppb.New(p.rebase(p.meta.SessionStart.AsTime()))
+	}
+	p.restamp(&p.meta.SessionId)
+
+	meta := p.meta
+	p.meta = nil
+	return p.out.ExecSession(meta)
+}
+
+func (p *Pacer) ExecCars(cars []*messaging.CarTelemetry) error {
+	p.cars = append(p.cars, cars...)
+	return nil
+}
+
+func (p *Pacer) ExecSession(meta *messaging.SessionMetadata) error {
+	p.meta = meta
+	return nil
+}
+
+// Close sends anything recorded after the last tick and closes the sink
+func (p *Pacer) Close() error {
+	if p.meta != nil {
+		if err := p.sendSession(); err != nil {
+			p.out.Close()
+			return err
+		}
+	}
+	if len(p.cars) > 0 {
+		if err := p.sendCars(p.cars); err != nil {
+			p.out.Close()
+			return err
+		}
+		p.cars = nil
+	}
+	return p.out.Close()
+}
+
+func (p *Pacer) GetMetrics() messaging.PublishMetrics {
+	metrics := p.out.GetMetrics()
+	if metrics.TotalBatches == 0 {
+		metrics.TotalBatches = p.batches
+	}
+	return metrics
+}
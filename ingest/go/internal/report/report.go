@@ -0,0 +1,172 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File statuses. A partial file was read but some of its batches failed or
+// are waiting in the spool, it is sent again on the next run.
+const (
+	StatusIngested    = "ingested"
+	StatusPartial     = "partial"
+	StatusFailed      = "failed"
+	StatusQuarantined = "quarantined"
+)
+
+// SinkStatus is the status of a file that was read, from what the sink did
+// with its batches. Failed when none of them were sent.
+func SinkStatus(batches, sentBatches, failedBatches, persistedBatches int) string {
+	switch {
+	case failedBatches == 0 && persistedBatches == 0:
+		return StatusIngested
+	case sentBatches == 0 && batches > 0:
+		return StatusFailed
+	default:
+		return StatusPartial
+	}
+}
+
+// File is what happened to one IBT file. It is kept in the ledger entry of
+// ingested files and in the run report for every file.
+type File struct {
+	Path      string   `json:"path"`
+	Archive   string   `json:"archive,omitempty"`
+	Status    string   `json:"status"`
+	Error     string   `json:"error,omitempty"`
+	ErrorKind string   `json:"error_kind,omitempty"`
+	Attempts  int      `json:"attempts"`
+	Sessions  []string `json:"sessions,omitempty"`
+	Track     string   `json:"track,omitempty"`
+	Laps      int      `json:"laps"`
+
+	// Records decoded from the file and what the sink did with them.
+	// Persisted batches are in the spool and have not reached the server yet.
+	Records          int   `json:"records"`
+	Batches          int   `json:"batches"`
+	SentBatches      int   `json:"sent_batches"`
+	Bytes            int64 `json:"bytes"`
+	FailedBatches    int   `json:"failed_batches"`
+	PersistedBatches int   `json:"persisted_batches"`
+
+	Started  time.Time `json:"started"`
+	Duration float64   `json:"duration_seconds"`
+}
+
+// Run sums the file reports of one ingest or watch run
+type Run struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitzero"`
+	Duration float64   `json:"duration_seconds"`
+
+	Files            int   `json:"files"`
+	Ingested         int   `json:"ingested"`
+	Partial          int   `json:"partial"`
+	Failed           int   `json:"failed"`
+	Quarantined      int   `json:"quarantined"`
+	Sessions         int   `json:"sessions"`
+	Laps             int   `json:"laps"`
+	Records          int   `json:"records"`
+	Batches          int   `json:"batches"`
+	SentBatches      int   `json:"sent_batches"`
+	Bytes            int64 `json:"bytes"`
+	FailedBatches    int   `json:"failed_batches"`
+	PersistedBatches int   `json:"persisted_batches"`
+
+	Reports []File `json:"reports"`
+}
+
+func NewRun(started time.Time) *Run {
+	return &Run{Started: started, Reports: []File{}}
+}
+
+// Add counts a file against the run
+func (r *Run) Add(file File) {
+	r.Files++
+	switch file.Status {
+	case StatusIngested:
+		r.Ingested++
+	case StatusPartial:
+		r.Partial++
+	case StatusQuarantined:
+		r.Quarantined++
+		r.Failed++
+	default:
+		r.Failed++
+	}
+
+	r.Sessions += len(file.Sessions)
+	r.Laps += file.Laps
+	r.Records += file.Records
+	r.Batches += file.Batches
+	r.SentBatches += file.SentBatches
+	r.Bytes += file.Bytes
+	r.FailedBatches += file.FailedBatches
+	r.PersistedBatches += file.PersistedBatches
+	r.Reports = append(r.Reports, file)
+}
+
+// Finish returns a copy of the run stamped with its end time
+func (r *Run) Finish(finished time.Time) *Run {
+	run := *r
+	run.Reports = append([]File(nil), r.Reports...)
+	run.Finished = finished
+	run.Duration = finished.Sub(r.Started).Seconds()
+	return &run
+}
+
+// Write saves the run as run-<start>.json in dir and returns the path
+func (r *Run) Write(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create report directory %s: %w\nAction: Check REPORT_DIR points to a writable location", dir, err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode run report: %w", err)
+	}
+
+	path := filepath.Join(dir, "run-"+r.Started.Format("20060102-150405")+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write run report %s: %w\nAction: Check disk space and file permissions", tmp, err)
+	}
+	return path, os.Rename(tmp, path)
+}
+
+// Latest reads the most recent run report in dir
+func Latest(dir string) (*Run, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, "", fmt.Errorf("failed to list reports in %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "run-") && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, "", fmt.Errorf("no run reports in %s\nAction: Reports are written when ingest or watch stops", dir)
+	}
+	sort.Strings(names)
+
+	path := filepath.Join(dir, names[len(names)-1])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read run report %s: %w", path, err)
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, "", fmt.Errorf("failed to parse run report %s: %w", path, err)
+	}
+	return &run, path, nil
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSinkStatus(t *testing.T) {
+	testCases := []struct {
+		name                             string
+		batches, sent, failed, persisted int
+		want                             string
+	}{
+		{"AllSent", 10, 10, 0, 0, StatusIngested},
+		{"NoBatches", 0, 0, 0, 0, StatusIngested},
+		{"NoneSent", 10, 0, 10, 0, StatusFailed},
+		{"AllSpooled", 10, 0, 0, 10, StatusFailed},
+		{"SomeFailed", 10, 8, 2, 0, StatusPartial},
+		{"SomeSpooled", 10, 7, 0, 3, StatusPartial},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SinkStatus(tc.batches, tc.sent, tc.failed, tc.persisted); got != tc.want {
+				t.Errorf("SinkStatus = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunAdd(t *testing.T) {
+	testCases := []struct {
+		name            string
+		statuses        []string
+		wantIngested    int
+		wantPartial     int
+		wantFailed      int
+		wantQuarantined int
+	}{
+		{"Ingested", []string{StatusIngested}, 1, 0, 0, 0},
+		{"Partial", []string{StatusPartial}, 0, 1, 0, 0},
+		{"Failed", []string{StatusFailed}, 0, 0, 1, 0},
+		{"QuarantinedCountsAsFailed", []string{StatusQuarantined}, 0, 0, 1, 1},
+		{"Mixed", []string{StatusIngested, StatusIngested, StatusPartial, StatusFailed, StatusQuarantined}, 2, 1, 2, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			run := NewRun(time.Now())
+			for _, status := range tc.statuses {
+				run.Add(File{
+					Status:        status,
+					Sessions:      []string{"s1"},
+					Laps:          2,
+					Records:       100,
+					Batches:       4,
+					SentBatches:   3,
+					Bytes:         1024,
+					FailedBatches: 1,
+				})
+			}
+
+			files := len(tc.statuses)
+			if run.Files != files || len(run.Reports) != files {
+				t.Errorf("Files = %d with %d reports, want %d", run.Files, len(run.Reports), files)
+			}
+			if run.Ingested != tc.wantIngested {
+				t.Errorf("Ingested = %d, want %d", run.Ingested, tc.wantIngested)
+			}
+			if run.Partial != tc.wantPartial {
+				t.Errorf("Partial = %d, want %d", run.Partial, tc.wantPartial)
+			}
+			if run.Failed != tc.wantFailed {
+				t.Errorf("Failed = %d, want %d", run.Failed, tc.wantFailed)
+			}
+			if run.Quarantined != tc.wantQuarantined {
+				t.Errorf("Quarantined = %d, want %d", run.Quarantined, tc.wantQuarantined)
+			}
+			if run.Sessions != files || run.Laps != 2*files || run.Records != 100*files {
+				t.Errorf("Sessions, Laps, Records = %d, %d, %d", run.Sessions, run.Laps, run.Records)
+			}
+			if run.Batches != 4*files || run.SentBatches != 3*files || run.FailedBatches != files || run.Bytes != int64(1024*files) {
+				t.Errorf("Batches, SentBatches, FailedBatches, Bytes = %d, %d, %d, %d", run.Batches, run.SentBatches, run.FailedBatches, run.Bytes)
+			}
+		})
+	}
+}
+
+func TestRunWriteAndLatest(t *testing.T) {
+	dir := t.TempDir()
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	run := NewRun(started)
+	run.Add(File{Path: "a.ibt", Status: StatusIngested})
+	if _, err := run.Finish(started.Add(time.Minute)).Write(dir); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	later := NewRun(started.Add(time.Hour))
+	later.Add(File{Path: "b.ibt", Status: StatusPartial})
+	if _, err := later.Finish(started.Add(2 * time.Hour)).Write(dir); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	latest, _, err := Latest(dir)
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if latest.Partial != 1 || len(latest.Reports) != 1 || latest.Reports[0].Path != "b.ibt" {
+		t.Errorf("Latest returned %+v, want the later run", latest)
+	}
+	if latest.Duration != time.Hour.Seconds() {
+		t.Errorf("Duration = %v, want %v", latest.Duration, time.Hour.Seconds())
+	}
+}
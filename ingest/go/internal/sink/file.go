@@ -0,0 +1,280 @@
+package sink
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Supported values for SINK_FILE_FORMAT
+const (
+	FormatJSON     = "json"
+	FormatProtobuf = "protobuf"
+)
+
+// WriterSink streams telemetry to a writer, either one JSON object per line
+// or as length-delimited TelemetryBatch messages for later replay.
+type WriterSink struct {
+	w       *bufio.Writer
+	closer  io.Closer
+	format  string
+	metrics messaging.PublishMetrics
+
+	// Shared with every other stdout sink, see stdout
+	mu *sync.Mutex
+
+	// Per-car records and session metadata go to their own streams
+	openStream func(name string) (io.Writer, io.Closer, error)
+	cars       *bufio.Writer
+	carsCloser io.Closer
+	carBatches int
+}
+
+// NewFileSink appends to <SINK_FILE_DIR>/<session>_<time>.ndjson (or .pb)
+func NewFileSink(cfg *config.Config, sessionID string, sessionTime time.Time) (*WriterSink, error) {
+	if err := os.MkdirAll(cfg.SinkFileDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create sink directory %s: %w\nAction: Check SINK_FILE_DIR points to a writable location", cfg.SinkFileDir, err)
+	}
+
+	ext := ".ndjson"
+	if cfg.SinkFileFormat == FormatProtobuf {
+		ext = ".pb"
+	}
+
+	name := fmt.Sprintf("%s_%s%s", sessionID, sessionTime.Format("20060102-150405"), ext)
+	f, err := openSinkFile(cfg.SinkFileDir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newWriterSink(bufio.NewWriterSize(f, 1024*1024), &sync.Mutex{}, f, cfg.SinkFileFormat)
+	s.openStream = func(stream string) (io.Writer, io.Closer, error) {
+		streamName := fmt.Sprintf("%s_%s_%s%s", sessionID, sessionTime.Format("20060102-150405"), stream, ext)
+		f, err := openSinkFile(cfg.SinkFileDir, streamName)
+		return f, f, err
+	}
+
+	return s, nil
+}
+
+func openSinkFile(dir, name string) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file %s: %w\nAction: Check disk space and file permissions", name, err)
+	}
+	return f, nil
+}
+
+// stdout is one buffer and lock for every stdout sink, so the batches of
+// concurrent workers come out whole instead of interleaved mid-record
+var stdout = struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}{w: bufio.NewWriterSize(os.Stdout, 1024*1024)}
+
+// NewStdoutSink writes to stdout, run with the progress display off
+func NewStdoutSink(cfg *config.Config) *WriterSink {
+	s := newWriterSink(stdout.w, &stdout.mu, nil, cfg.SinkFileFormat)
+	// Cars and session metadata go through s.w with the ticks, writing to
+	// os.Stdout directly would jump ahead of what is still buffered
+	s.openStream = func(string) (io.Writer, io.Closer, error) {
+		return s.w, nil, nil
+	}
+	return s
+}
+
+func newWriterSink(w *bufio.Writer, mu *sync.Mutex, closer io.Closer, format string) *WriterSink {
+	if format == "" {
+		format = FormatJSON
+	}
+
+	return &WriterSink{
+		w:      w,
+		mu:     mu,
+		closer: closer,
+		format: format,
+	}
+}
+
+func (s *WriterSink) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	records, err := messaging.TransformStructBatch(ticks, channels)
+	if err != nil {
+		return fmt.Errorf("failed to transform struct batch: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var written int
+	switch s.format {
+	case FormatProtobuf:
+		batch := &messaging.TelemetryBatch{
+			Records:   records,
+			BatchId:   fmt.Sprintf("batch_%d_%d", ticks[0].WorkerID, s.metrics.TotalBatches),
+			SessionId: records[0].SessionId,
+			WorkerId:  uint32(ticks[0].WorkerID),
+			Timestamp: timestamppb.Now(),
+		}
+		written, err = protodelim.MarshalTo(s.w, batch)
+	case FormatJSON:
+		for _, record := range records {
+			line, merr := protojson.Marshal(record)
+			if merr != nil {
+				err = merr
+				break
+			}
+			line = append(line, '\n')
+
+			n, werr := s.w.Write(line)
+			written += n
+			if werr != nil {
+				err = werr
+				break
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown sink format %q\nAction: Set SINK_FILE_FORMAT to json or protobuf", s.format)
+	}
+
+	if err != nil {
+		s.metrics.FailedBatches++
+		return fmt.Errorf("failed to write batch: %w", err)
+	}
+
+	s.metrics.TotalBatches++
+	s.metrics.SentBatches++
+	s.metrics.TotalRecords += len(records)
+	s.metrics.TotalBytes += int64(written)
+	s.metrics.LastFlush = time.Now()
+
+	return nil
+}
+
+func (s *WriterSink) ExecCars(cars []*messaging.CarTelemetry) error {
+	if len(cars) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cars == nil {
+		w, closer, err := s.openStream("cars")
+		if err != nil {
+			return err
+		}
+		// Stdout is already buffered, a second buffer would split lines
+		if buffered, ok := w.(*bufio.Writer); ok {
+			s.cars = buffered
+		} else {
+			s.cars = bufio.NewWriterSize(w, 256*1024)
+		}
+		s.carsCloser = closer
+	}
+
+	if s.format == FormatProtobuf {
+		batch := &messaging.CarTelemetryBatch{
+			Records:   cars,
+			BatchId:   fmt.Sprintf("%s%d", messaging.CarBatchPrefix, s.carBatches),
+			SessionId: cars[0].SessionId,
+			Timestamp: timestamppb.Now(),
+		}
+		s.carBatches++
+
+		if _, err := protodelim.MarshalTo(s.cars, batch); err != nil {
+			return fmt.Errorf("failed to write car batch: %w", err)
+		}
+		return nil
+	}
+
+	for _, car := range cars {
+		line, err := protojson.Marshal(car)
+		if err != nil {
+			return fmt.Errorf("failed to encode car record: %w", err)
+		}
+
+		if _, err := s.cars.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write car record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *WriterSink) ExecSession(meta *messaging.SessionMetadata) error {
+	if meta == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, closer, err := s.openStream("session")
+	if err != nil {
+		return err
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	if s.format == FormatProtobuf {
+		_, err = protodelim.MarshalTo(w, meta)
+	} else {
+		var line []byte
+		line, err = protojson.Marshal(meta)
+		if err == nil {
+			_, err = w.Write(append(line, '\n'))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write session metadata: %w", err)
+	}
+	return nil
+}
+
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cars != nil {
+		if err := s.cars.Flush(); err != nil {
+			return err
+		}
+		if s.carsCloser != nil {
+			if err := s.carsCloser.Close(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
+}
+
+func (s *WriterSink) GetMetrics() messaging.PublishMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.metrics
+}
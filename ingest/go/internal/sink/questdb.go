@@ -0,0 +1,287 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/channels"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+	qdb "github.com/questdb/go-questdb-client/v4"
+)
+
+// QuestDBSink writes ticks straight into the TelemetryTicks table over ILP,
+// using the same columns as telemetryService so the dashboard can read them.
+type QuestDBSink struct {
+	sender  qdb.LineSender
+	metrics messaging.PublishMetrics
+	mu      sync.Mutex
+}
+
+func NewQuestDBSink(cfg *config.Config) (*QuestDBSink, error) {
+	sender, err := qdb.NewLineSender(
+		context.Background(),
+		qdb.WithHttp(),
+		qdb.WithAddress(cfg.QuestDBAddress),
+		qdb.WithAutoFlushDisabled(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create QuestDB sender for %s: %w\nAction: Verify QuestDB is running and QUESTDB_ADDR is correct", cfg.QuestDBAddress, err)
+	}
+
+	return &QuestDBSink{sender: sender}, nil
+}
+
+func (q *QuestDBSink) ExecStructs(ticks []*ibt.TelemetryTick, extra []map[string]float64) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	records, err := messaging.TransformStructBatch(ticks, extra)
+	if err != nil {
+		return fmt.Errorf("failed to transform struct batch: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ctx := context.Background()
+	for _, record := range records {
+		row := q.sender.Table("TelemetryTicks").
+			Symbol("session_id", symbol(record.SessionId)).
+			Symbol("track_name", symbol(record.TrackName)).
+			Symbol("track_id", symbol(record.TrackId)).
+			Symbol("lap_id", symbol(record.LapId)).
+			Symbol("session_num", symbol(record.SessionNum)).
+			Symbol("session_type", symbol(record.SessionType)).
+			Symbol("session_name", symbol(record.SessionName)).
+			Symbol("car_id", symbol(record.CarId)).
+			Int64Column("gear", validInt(record.Gear)).
+			Int64Column("player_car_position", validInt(uint32(finite(record.PlayerCarPosition)))).
+			Float64Column("speed", finite(record.Speed)).
+			Float64Column("lap_dist_pct", finite(record.LapDistPct)).
+			Float64Column("session_time", finite(record.SessionTime)).
+			Float64Column("lat", finite(record.Lat)).
+			Float64Column("lon", finite(record.Lon)).
+			Float64Column("lap_current_lap_time", finite(record.LapCurrentLapTime)).
+			Float64Column("lapLastLapTime", finite(record.LapLastLapTime)).
+			Float64Column("lapDeltaToBestLap", finite(record.LapDeltaToBestLap)).
+			Float64Column("throttle", finite(record.Throttle)).
+			Float64Column("brake", finite(record.Brake)).
+			Float64Column("steering_wheel_angle", finite(record.SteeringWheelAngle)).
+			Float64Column("rpm", finite(record.Rpm)).
+			Float64Column("velocity_x", finite(record.VelocityX)).
+			Float64Column("velocity_y", finite(record.VelocityY)).
+			Float64Column("velocity_z", finite(record.VelocityZ)).
+			Float64Column("fuel_level", finite(record.FuelLevel)).
+			Float64Column("alt", finite(record.Alt)).
+			Float64Column("lat_accel", finite(record.LatAccel)).
+			Float64Column("long_accel", finite(record.LongAccel)).
+			Float64Column("vert_accel", finite(record.VertAccel)).
+			Float64Column("pitch", finite(record.Pitch)).
+			Float64Column("roll", finite(record.Roll)).
+			Float64Column("yaw", finite(record.Yaw)).
+			Float64Column("yaw_north", finite(record.YawNorth)).
+			Float64Column("voltage", finite(record.Voltage)).
+			Float64Column("waterTemp", finite(record.WaterTemp)).
+			Float64Column("lFpressure", finite(record.LFpressure)).
+			Float64Column("rFpressure", finite(record.RFpressure)).
+			Float64Column("lRpressure", finite(record.LRpressure)).
+			Float64Column("rRpressure", finite(record.RRpressure)).
+			Float64Column("lFtempM", finite(record.LFtempM)).
+			Float64Column("rFtempM", finite(record.RFtempM)).
+			Float64Column("lRtempM", finite(record.LRtempM)).
+			Float64Column("rRtempM", finite(record.RRtempM))
+
+		// Manifest channels, QuestDB adds the columns on first write
+		for name, value := range record.Channels {
+			row = row.Float64Column(channels.Column(name), finite(value))
+		}
+
+		if err := row.At(ctx, record.TickTime.AsTime()); err != nil {
+			q.metrics.FailedBatches++
+			return fmt.Errorf("failed to encode row for QuestDB: %w", err)
+		}
+	}
+
+	if err := q.sender.Flush(ctx); err != nil {
+		q.metrics.FailedBatches++
+		return fmt.Errorf("failed to flush %d rows to QuestDB: %w\nAction: Check QuestDB is reachable and has disk space", len(records), err)
+	}
+
+	q.metrics.TotalBatches++
+	q.metrics.SentBatches++
+	q.metrics.TotalRecords += len(records)
+	q.metrics.LastFlush = time.Now()
+
+	return nil
+}
+
+// ExecCars writes per-car records to the CarTelemetry table
+func (q *QuestDBSink) ExecCars(cars []*messaging.CarTelemetry) error {
+	if len(cars) == 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ctx := context.Background()
+	for _, car := range cars {
+		err := q.sender.Table("CarTelemetry").
+			Symbol("session_id", symbol(car.SessionId)).
+			Symbol("session_num", symbol(car.SessionNum)).
+			Symbol("car_idx", strconv.Itoa(int(car.CarIdx))).
+			Int64Column("lap", int64(car.Lap)).
+			Int64Column("position", int64(car.Position)).
+			Int64Column("class_position", int64(car.ClassPosition)).
+			Int64Column("track_surface", int64(car.TrackSurface)).
+			BoolColumn("on_pit_road", car.OnPitRoad).
+			Float64Column("lap_dist_pct", finite(car.LapDistPct)).
+			Float64Column("est_time", finite(car.EstTime)).
+			Float64Column("f2_time", finite(car.F2Time)).
+			Float64Column("session_time", finite(car.SessionTime)).
+			At(ctx, car.TickTime.AsTime())
+		if err != nil {
+			q.metrics.FailedBatches++
+			return fmt.Errorf("failed to encode car row for QuestDB: %w", err)
+		}
+	}
+
+	if err := q.sender.Flush(ctx); err != nil {
+		q.metrics.FailedBatches++
+		return fmt.Errorf("failed to flush %d car rows to QuestDB: %w\nAction: Check QuestDB is reachable and has disk space", len(cars), err)
+	}
+
+	// Counted like PubSub, car batches are sent but not tick batches
+	q.metrics.SentBatches++
+	q.metrics.LastFlush = time.Now()
+
+	return nil
+}
+
+// ExecSession writes one SessionMetadata row, the roster and setup are
+// stored as JSON
+func (q *QuestDBSink) ExecSession(meta *messaging.SessionMetadata) error {
+	if meta == nil {
+		return nil
+	}
+
+	drivers, err := json.Marshal(meta.Drivers)
+	if err != nil {
+		return fmt.Errorf("failed to encode driver roster: %w", err)
+	}
+
+	sessions, err := json.Marshal(meta.Sessions)
+	if err != nil {
+		return fmt.Errorf("failed to encode sessions: %w", err)
+	}
+
+	weather := meta.Weather
+	if weather == nil {
+		weather = &messaging.SessionMetadata_Weather{}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ctx := context.Background()
+	err = q.sender.Table("SessionMetadata").
+		Symbol("session_id", symbol(meta.SessionId)).
+		Symbol("track_name", symbol(meta.TrackName)).
+		Symbol("track_id", symbol(meta.TrackId)).
+		Symbol("car_name", symbol(meta.CarName)).
+		StringColumn("track_config", meta.TrackConfig).
+		StringColumn("track_city", meta.TrackCity).
+		StringColumn("track_country", meta.TrackCountry).
+		StringColumn("track_length", meta.TrackLength).
+		StringColumn("series_id", meta.SeriesId).
+		StringColumn("season_id", meta.SeasonId).
+		StringColumn("event_type", meta.EventType).
+		StringColumn("category", meta.Category).
+		StringColumn("weather_type", weather.Type).
+		StringColumn("skies", weather.Skies).
+		StringColumn("track_temp", weather.TrackTemp).
+		StringColumn("air_temp", weather.AirTemp).
+		StringColumn("air_pressure", weather.AirPressure).
+		StringColumn("wind_speed", weather.WindSpeed).
+		StringColumn("wind_dir", weather.WindDir).
+		StringColumn("relative_humidity", weather.RelativeHumidity).
+		StringColumn("fog_level", weather.FogLevel).
+		StringColumn("time_of_day", weather.TimeOfDay).
+		StringColumn("event_date", weather.Date).
+		Int64Column("player_car_idx", int64(meta.PlayerCarIdx)).
+		StringColumn("car_path", meta.CarPath).
+		StringColumn("setup_name", meta.SetupName).
+		StringColumn("car_setup", meta.CarSetup).
+		StringColumn("drivers", string(drivers)).
+		StringColumn("sessions", string(sessions)).
+		At(ctx, meta.SessionStart.AsTime())
+	if err != nil {
+		return fmt.Errorf("failed to encode session metadata for QuestDB: %w", err)
+	}
+
+	if err := q.sender.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush session metadata to QuestDB: %w\nAction: Check QuestDB is reachable and has disk space", err)
+	}
+
+	return nil
+}
+
+func (q *QuestDBSink) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.sender.Close(context.Background())
+}
+
+func (q *QuestDBSink) GetMetrics() messaging.PublishMetrics {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.metrics
+}
+
+// symbol, finite and validInt must give the same values as sanitise,
+// validateDouble and validateInt in telemetryService's persistance package,
+// otherwise rows written by this sink and by the service do not match up.
+
+// symbol stores empty symbols as "unknown" and replaces the characters ILP
+// treats specially with underscores
+func symbol(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', ' ', '=', '\n', '\r', '"', '\'', '\\':
+			return '_'
+		}
+		return r
+	}, value)
+	return strings.TrimSpace(value)
+}
+
+func finite(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	if value == math.MaxFloat64 || value == -math.MaxFloat64 {
+		return 0
+	}
+	return value
+}
+
+// validInt maps iRacing's 0xFFFFFFFF "no value" sentinel to 0
+func validInt(value uint32) int64 {
+	if value == 0xFFFFFFFF {
+		return 0
+	}
+	return int64(value)
+}
@@ -0,0 +1,60 @@
+package sink
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+)
+
+// Supported values for INGEST_SINK
+const (
+	KindRabbitMQ = "rabbitmq"
+	KindQuestDB  = "questdb"
+	KindFile     = "file"
+	KindStdout   = "stdout"
+)
+
+// Sink receives the telemetry for a single session group. messaging.PubSub
+// is the RabbitMQ implementation, the others let ingest run without the
+// broker stack.
+type Sink interface {
+	ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error
+	ExecCars(cars []*messaging.CarTelemetry) error
+	ExecSession(meta *messaging.SessionMetadata) error
+	Close() error
+	GetMetrics() messaging.PublishMetrics
+}
+
+// New builds the sink selected by cfg.Sink for one session group.
+func New(cfg *config.Config, pool *messaging.ConnectionPool, sessionID string, sessionTime time.Time, workerID int) (Sink, error) {
+	switch cfg.Sink {
+	case "", KindRabbitMQ:
+		if cfg.DisableRabbitMQ || pool == nil {
+			return &NoOpSink{}, nil
+		}
+		return messaging.NewPubSub(sessionID, sessionTime, cfg, pool, workerID), nil
+	case KindQuestDB:
+		return NewQuestDBSink(cfg)
+	case KindFile:
+		return NewFileSink(cfg, sessionID, sessionTime)
+	case KindStdout:
+		return NewStdoutSink(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown sink %q\nAction: Set INGEST_SINK to one of rabbitmq, questdb, file or stdout", cfg.Sink)
+	}
+}
+
+// NoOpSink discards everything, used when DISABLE_RABBITMQ is set
+type NoOpSink struct{}
+
+func (n *NoOpSink) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
+	return nil
+}
+
+func (n *NoOpSink) ExecCars(cars []*messaging.CarTelemetry) error     { return nil }
+func (n *NoOpSink) ExecSession(meta *messaging.SessionMetadata) error { return nil }
+func (n *NoOpSink) Close() error                                      { return nil }
+func (n *NoOpSink) GetMetrics() messaging.PublishMetrics              { return messaging.PublishMetrics{} }
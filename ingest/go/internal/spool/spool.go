@@ -0,0 +1,185 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fileExt = ".pb"
+
+// ErrFull is returned when writing a batch would take the spool over its size cap.
+var ErrFull = errors.New("spool is full")
+
+// Entry is a single batch waiting on disk to be replayed.
+type Entry struct {
+	BatchID  string
+	Path     string
+	Size     int64
+	QueuedAt time.Time
+}
+
+// Spool stores marshalled TelemetryBatch payloads that could not be
+// published, one file per batch, so they can be replayed once the broker
+// is reachable again.
+type Spool struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+
+	// Bytes on disk, counted at startup and on every listing and kept up to
+	// date by Write and Remove in between
+	used int64
+}
+
+func New(dir string, maxBytes int64) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory %s: %w\nAction: Check SPOOL_DIR points to a writable location", dir, err)
+	}
+
+	s := &Spool{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}
+	if _, err := s.list(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Spool) Dir() string {
+	return s.dir
+}
+
+// Write persists a batch payload. Files are written to a temp name and
+// renamed so a replay never picks up a partially written batch.
+func (s *Spool) Write(batchID string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxBytes > 0 && s.used+int64(len(data)) > s.maxBytes {
+		return fmt.Errorf("%w: %d of %d bytes used\nAction: Run 'ingest spool replay' once RabbitMQ is back or raise SPOOL_MAX_BYTES", ErrFull, s.used, s.maxBytes)
+	}
+
+	name := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), batchID, fileExt)
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write spooled batch %s: %w\nAction: Check disk space and file permissions", batchID, err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	s.used += int64(len(data))
+	return nil
+}
+
+// List returns the spooled batches oldest first.
+func (s *Spool) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+func (s *Spool) list() ([]Entry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory %s: %w", s.dir, err)
+	}
+
+	var used int64
+	entries := make([]Entry, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		stamp, batchID, ok := strings.Cut(strings.TrimSuffix(name, fileExt), "_")
+		if !ok {
+			continue
+		}
+
+		queuedAt := info.ModTime()
+		if nanos, err := strconv.ParseInt(stamp, 10, 64); err == nil {
+			queuedAt = time.Unix(0, nanos)
+		}
+
+		entries = append(entries, Entry{
+			BatchID:  batchID,
+			Path:     filepath.Join(s.dir, name),
+			Size:     info.Size(),
+			QueuedAt: queuedAt,
+		})
+		used += info.Size()
+	}
+	s.used = used
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].QueuedAt.Before(entries[j].QueuedAt)
+	})
+
+	return entries, nil
+}
+
+func (s *Spool) Read(entry Entry) ([]byte, error) {
+	return os.ReadFile(entry.Path)
+}
+
+func (s *Spool) Remove(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(entry.Path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	s.used = max(s.used-entry.Size, 0)
+	return nil
+}
+
+// Purge deletes every spooled batch and returns how many were removed.
+func (s *Spool) Purge() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.list()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, entry := range entries {
+		if err := os.Remove(entry.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return i, err
+		}
+		s.used = max(s.used-entry.Size, 0)
+	}
+
+	return len(entries), nil
+}
+
+// Size returns the number of bytes currently held in the spool.
+func (s *Spool) Size() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.list(); err != nil {
+		return 0, err
+	}
+	return s.used, nil
+}
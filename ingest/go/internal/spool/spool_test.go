@@ -0,0 +1,118 @@
+package spool
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSpoolWriteAndReplay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		maxBytes int64
+		batches  []string
+		wantIDs  []string
+		wantFull bool
+	}{
+		{"Unlimited", 0, []string{"batch_a", "batch_b", "batch_c"}, []string{"batch_a", "batch_b", "batch_c"}, false},
+		{"FitsCap", 30, []string{"batch_a", "batch_b"}, []string{"batch_a", "batch_b"}, false},
+		{"OverCap", 20, []string{"batch_a", "batch_b", "batch_c"}, []string{"batch_a", "batch_b"}, true},
+		{"IDWithUnderscores", 0, []string{"batch_0a1b_2_0_500"}, []string{"batch_0a1b_2_0_500"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := New(t.TempDir(), tc.maxBytes)
+			if err != nil {
+				t.Fatalf("Failed to create spool: %v", err)
+			}
+
+			full := false
+			for _, id := range tc.batches {
+				err := s.Write(id, payload(id))
+				if errors.Is(err, ErrFull) {
+					full = true
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Write %s failed: %v", id, err)
+				}
+			}
+			if full != tc.wantFull {
+				t.Errorf("spool full = %v, want %v", full, tc.wantFull)
+			}
+
+			entries, err := s.List()
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(entries) != len(tc.wantIDs) {
+				t.Fatalf("List returned %d entries, want %d", len(entries), len(tc.wantIDs))
+			}
+
+			// Replay reads oldest first and removes each batch once sent
+			for i, entry := range entries {
+				if entry.BatchID != tc.wantIDs[i] {
+					t.Errorf("entry %d is %s, want %s", i, entry.BatchID, tc.wantIDs[i])
+				}
+				data, err := s.Read(entry)
+				if err != nil {
+					t.Fatalf("Read %s failed: %v", entry.BatchID, err)
+				}
+				if !bytes.Equal(data, payload(tc.wantIDs[i])) {
+					t.Errorf("entry %s has payload %q", entry.BatchID, data)
+				}
+				if err := s.Remove(entry); err != nil {
+					t.Fatalf("Remove %s failed: %v", entry.BatchID, err)
+				}
+			}
+
+			size, err := s.Size()
+			if err != nil {
+				t.Fatalf("Size failed: %v", err)
+			}
+			if size != 0 {
+				t.Errorf("Size after replay = %d, want 0", size)
+			}
+		})
+	}
+}
+
+func TestSpoolSizeTracksWrites(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir, 25)
+	if err != nil {
+		t.Fatalf("Failed to create spool: %v", err)
+	}
+
+	for _, id := range []string{"batch_a", "batch_b"} {
+		if err := s.Write(id, payload(id)); err != nil {
+			t.Fatalf("Write %s failed: %v", id, err)
+		}
+	}
+
+	// A new spool over the same folder counts what is already there
+	reopened, err := New(dir, 25)
+	if err != nil {
+		t.Fatalf("Failed to reopen spool: %v", err)
+	}
+	if err := reopened.Write("batch_c", payload("batch_c")); !errors.Is(err, ErrFull) {
+		t.Errorf("Write to a full reopened spool returned %v, want ErrFull", err)
+	}
+
+	removed, err := reopened.Purge()
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Purge removed %d batches, want 2", removed)
+	}
+	if err := reopened.Write("batch_c", payload("batch_c")); err != nil {
+		t.Errorf("Write after Purge failed: %v", err)
+	}
+}
+
+// payload is 10 bytes per batch
+func payload(id string) []byte {
+	return []byte(id + "...")[:10]
+}
@@ -0,0 +1,324 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/ibt"
+)
+
+// Issue kinds
+const (
+	KindNaN          = "nan"
+	KindInf          = "inf"
+	KindSentinel     = "sentinel"
+	KindTimeGap      = "session_time_gap"
+	KindTimeReversal = "session_time_reversal"
+	KindGPSDropout   = "gps_dropout"
+	KindLapDistJump  = "lap_dist_jump"
+	KindTruncated    = "truncated"
+	KindReadError    = "read_error"
+)
+
+// Errors would store wrong values, warnings are odd but storable
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var severities = map[string]string{
+	KindNaN:          SeverityError,
+	KindInf:          SeverityError,
+	KindSentinel:     SeverityWarning,
+	KindTimeGap:      SeverityWarning,
+	KindTimeReversal: SeverityError,
+	KindGPSDropout:   SeverityWarning,
+	KindLapDistJump:  SeverityWarning,
+	KindTruncated:    SeverityError,
+	KindReadError:    SeverityError,
+}
+
+const (
+	// IBT files tick at 60Hz, anything over half a second is missing data
+	maxTickGap = 0.5
+
+	// A car cannot cover this much of a lap in one tick
+	maxLapDistJump = 0.05
+
+	// Below this the car is parked and a lost GPS fix does not matter
+	minMovingSpeed = 1.0
+
+	// The irsdk value for an unset int, telemetryService stores it as 0
+	sentinel = 0xFFFFFFFF
+)
+
+// Issue is a run of consecutive ticks with the same problem
+type Issue struct {
+	Kind     string  `json:"kind"`
+	Severity string  `json:"severity"`
+	Field    string  `json:"field,omitempty"`
+	Group    int     `json:"group"`
+	Session  int     `json:"session_num"`
+	Lap      int     `json:"lap"`
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Ticks    int     `json:"ticks"`
+	Detail   string  `json:"detail,omitempty"`
+}
+
+// Report is the result for one IBT file
+type Report struct {
+	File      string         `json:"file"`
+	SessionID string         `json:"session_id"`
+	Ticks     int            `json:"ticks"`
+	Expected  int            `json:"expected"`
+	Errors    int            `json:"errors"`
+	Warnings  int            `json:"warnings"`
+	Counts    map[string]int `json:"counts"`
+	Issues    []Issue        `json:"issues"`
+}
+
+// NewReport starts a report, expected is the record count from the headers
+func NewReport(file, sessionID string, expected int) *Report {
+	return &Report{
+		File:      file,
+		SessionID: sessionID,
+		Expected:  expected,
+		Counts:    make(map[string]int),
+		Issues:    []Issue{},
+	}
+}
+
+// Add records an issue and counts it against its severity
+func (r *Report) Add(issue Issue) {
+	issue.Severity = severities[issue.Kind]
+	if issue.Severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Counts[issue.Kind]++
+	r.Issues = append(r.Issues, issue)
+}
+
+// Sort orders the issues by group and session time
+func (r *Report) Sort() {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		if r.Issues[i].Group != r.Issues[j].Group {
+			return r.Issues[i].Group < r.Issues[j].Group
+		}
+		return r.Issues[i].Start < r.Issues[j].Start
+	})
+}
+
+type floatField struct {
+	name string
+	get  func(*ibt.TelemetryTick) float64
+}
+
+// floatFields are the TelemetryTick fields checked for NaN and Inf
+var floatFields = []floatField{
+	{"LapDistPct", func(t *ibt.TelemetryTick) float64 { return t.LapDistPct }},
+	{"Speed", func(t *ibt.TelemetryTick) float64 { return t.Speed }},
+	{"PlayerCarPosition", func(t *ibt.TelemetryTick) float64 { return t.PlayerCarPosition }},
+	{"Throttle", func(t *ibt.TelemetryTick) float64 { return t.Throttle }},
+	{"Brake", func(t *ibt.TelemetryTick) float64 { return t.Brake }},
+	{"RPM", func(t *ibt.TelemetryTick) float64 { return t.RPM }},
+	{"SteeringWheelAngle", func(t *ibt.TelemetryTick) float64 { return t.SteeringWheelAngle }},
+	{"VelocityX", func(t *ibt.TelemetryTick) float64 { return t.VelocityX }},
+	{"VelocityY", func(t *ibt.TelemetryTick) float64 { return t.VelocityY }},
+	{"VelocityZ", func(t *ibt.TelemetryTick) float64 { return t.VelocityZ }},
+	{"Lat", func(t *ibt.TelemetryTick) float64 { return t.Lat }},
+	{"Lon", func(t *ibt.TelemetryTick) float64 { return t.Lon }},
+	{"Alt", func(t *ibt.TelemetryTick) float64 { return t.Alt }},
+	{"Pitch", func(t *ibt.TelemetryTick) float64 { return t.Pitch }},
+	{"Roll", func(t *ibt.TelemetryTick) float64 { return t.Roll }},
+	{"Yaw", func(t *ibt.TelemetryTick) float64 { return t.Yaw }},
+	{"YawNorth", func(t *ibt.TelemetryTick) float64 { return t.YawNorth }},
+	{"LatAccel", func(t *ibt.TelemetryTick) float64 { return t.LatAccel }},
+	{"LongAccel", func(t *ibt.TelemetryTick) float64 { return t.LongAccel }},
+	{"VertAccel", func(t *ibt.TelemetryTick) float64 { return t.VertAccel }},
+	{"SessionTime", func(t *ibt.TelemetryTick) float64 { return t.SessionTime }},
+	{"FuelLevel", func(t *ibt.TelemetryTick) float64 { return t.FuelLevel }},
+	{"Voltage", func(t *ibt.TelemetryTick) float64 { return t.Voltage }},
+	{"WaterTemp", func(t *ibt.TelemetryTick) float64 { return t.WaterTemp }},
+	{"LapLastLapTime", func(t *ibt.TelemetryTick) float64 { return t.LapLastLapTime }},
+	{"LapDeltaToBestLap", func(t *ibt.TelemetryTick) float64 { return t.LapDeltaToBestLap }},
+	{"LapCurrentLapTime", func(t *ibt.TelemetryTick) float64 { return t.LapCurrentLapTime }},
+	{"LFpressure", func(t *ibt.TelemetryTick) float64 { return t.LFpressure }},
+	{"RFpressure", func(t *ibt.TelemetryTick) float64 { return t.RFpressure }},
+	{"LRpressure", func(t *ibt.TelemetryTick) float64 { return t.LRpressure }},
+	{"RRpressure", func(t *ibt.TelemetryTick) float64 { return t.RRpressure }},
+	{"LFtempM", func(t *ibt.TelemetryTick) float64 { return t.LFtempM }},
+	{"RFtempM", func(t *ibt.TelemetryTick) float64 { return t.RFtempM }},
+	{"LRtempM", func(t *ibt.TelemetryTick) float64 { return t.LRtempM }},
+	{"RRtempM", func(t *ibt.TelemetryTick) float64 { return t.RRtempM }},
+}
+
+type intField struct {
+	name string
+	get  func(*ibt.TelemetryTick) uint32
+}
+
+// intFields are checked for the unset sentinel. PlayerCarPosition is a
+// whole number that telemetryService reads as uint32.
+var intFields = []intField{
+	{"Gear", func(t *ibt.TelemetryTick) uint32 { return uint32(t.Gear) }},
+	{"LapID", func(t *ibt.TelemetryTick) uint32 { return uint32(t.LapID) }},
+	{"PlayerCarIdx", func(t *ibt.TelemetryTick) uint32 { return uint32(t.PlayerCarIdx) }},
+	{"SessionNum", func(t *ibt.TelemetryTick) uint32 { return uint32(t.SessionNum) }},
+	{"PlayerCarPosition", func(t *ibt.TelemetryTick) uint32 { return uint32(t.PlayerCarPosition) }},
+}
+
+// Checker is a sink that checks the ticks of one group instead of sending
+// them anywhere. Problems spanning consecutive ticks become a single Issue.
+type Checker struct {
+	report   *Report
+	group    int
+	expected int
+	ticks    int
+
+	open map[string]*Issue
+	prev *ibt.TelemetryTick
+}
+
+// NewChecker checks one group into report, expected is the group's record
+// count from its headers, 0 when unknown.
+func NewChecker(report *Report, group, expected int) *Checker {
+	return &Checker{
+		report:   report,
+		group:    group,
+		expected: expected,
+		open:     make(map[string]*Issue),
+	}
+}
+
+func (c *Checker) ExecStructs(ticks []*ibt.TelemetryTick, channels []map[string]float64) error {
+	for i, tick := range ticks {
+		var tickChannels map[string]float64
+		if i < len(channels) {
+			tickChannels = channels[i]
+		}
+		c.check(tick, tickChannels)
+
+		// The processor reuses ticks once the batch is handed over
+		prev := *tick
+		c.prev = &prev
+		c.ticks++
+	}
+	return nil
+}
+
+func (c *Checker) check(tick *ibt.TelemetryTick, channels map[string]float64) {
+	seen := make(map[string]bool)
+	flag := func(kind, field, detail string) {
+		key := kind + "/" + field
+		seen[key] = true
+		if issue, ok := c.open[key]; ok {
+			issue.End = tick.SessionTime
+			issue.Ticks++
+			return
+		}
+		c.open[key] = &Issue{
+			Kind:    kind,
+			Field:   field,
+			Group:   c.group,
+			Session: int(tick.SessionNum),
+			Lap:     int(tick.LapID),
+			Start:   tick.SessionTime,
+			End:     tick.SessionTime,
+			Ticks:   1,
+			Detail:  detail,
+		}
+	}
+
+	for _, field := range floatFields {
+		checkFloat(flag, field.name, field.get(tick))
+	}
+	for name, v := range channels {
+		checkFloat(flag, name, v)
+	}
+
+	for _, field := range intFields {
+		if field.get(tick) == sentinel {
+			flag(KindSentinel, field.name, "stored as 0 by telemetryService")
+		}
+	}
+
+	if tick.Speed > minMovingSpeed && tick.Lat == 0 && tick.Lon == 0 {
+		flag(KindGPSDropout, "", "car moving with no position")
+	}
+
+	if prev := c.prev; prev != nil && prev.SessionNum == tick.SessionNum {
+		dt := tick.SessionTime - prev.SessionTime
+		switch {
+		case dt < 0:
+			flag(KindTimeReversal, "SessionTime", fmt.Sprintf("%.3f -> %.3f", prev.SessionTime, tick.SessionTime))
+		case dt > maxTickGap:
+			flag(KindTimeGap, "SessionTime", fmt.Sprintf("%.3fs missing after %.3f", dt, prev.SessionTime))
+		}
+
+		// Crossing the line wraps 1 -> 0 without an impossible jump
+		jump := math.Abs(tick.LapDistPct - prev.LapDistPct)
+		wrapped := prev.LapDistPct > 1-maxLapDistJump && tick.LapDistPct < maxLapDistJump
+		if prev.LapID == tick.LapID && jump > maxLapDistJump && !wrapped {
+			flag(KindLapDistJump, "LapDistPct", fmt.Sprintf("%.3f -> %.3f", prev.LapDistPct, tick.LapDistPct))
+		}
+	}
+
+	// Close the runs that ended on this tick
+	for key, issue := range c.open {
+		if !seen[key] {
+			c.report.Add(*issue)
+			delete(c.open, key)
+		}
+	}
+}
+
+func checkFloat(flag func(kind, field, detail string), name string, v float64) {
+	switch {
+	case math.IsNaN(v):
+		flag(KindNaN, name, "")
+	case math.IsInf(v, 0):
+		flag(KindInf, name, "")
+	}
+}
+
+func (c *Checker) ExecCars(cars []*messaging.CarTelemetry) error     { return nil }
+func (c *Checker) ExecSession(meta *messaging.SessionMetadata) error { return nil }
+
+// Close ends any open runs and flags a group that stopped short of the
+// record count in its headers, or whose last tick was never written.
+func (c *Checker) Close() error {
+	for _, issue := range c.open {
+		c.report.Add(*issue)
+	}
+	c.open = make(map[string]*Issue)
+
+	if c.expected > 0 && c.ticks < c.expected {
+		c.report.Add(Issue{
+			Kind:   KindTruncated,
+			Group:  c.group,
+			Ticks:  c.expected - c.ticks,
+			Detail: fmt.Sprintf("decoded %d of %d records", c.ticks, c.expected),
+		})
+	} else if c.prev != nil && c.ticks > 1 && c.prev.SessionTime == 0 {
+		c.report.Add(Issue{
+			Kind:    KindTruncated,
+			Group:   c.group,
+			Session: int(c.prev.SessionNum),
+			Lap:     int(c.prev.LapID),
+			Ticks:   1,
+			Detail:  "last record is empty",
+		})
+	}
+
+	c.report.Ticks += c.ticks
+	return nil
+}
+
+func (c *Checker) GetMetrics() messaging.PublishMetrics {
+	return messaging.PublishMetrics{TotalRecords: c.ticks}
+}
@@ -3,12 +3,17 @@ package worker
 import (
 	"context"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/metrics"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/report"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/spool"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -40,17 +45,38 @@ type WorkerPool struct {
 	logger     *zap.Logger
 
 	workerMetrics   []WorkerMetrics
+	activeSinks     []sink.Sink
 	progressDisplay *ProgressDisplay
+	ledger          *ledger.Ledger
+	quarantine      *ledger.Quarantine
+
+	// Told about files that ended up in neither the ledger nor quarantine
+	unsent func(path string)
+
+	// Retries waiting out their delay, cancelled by Stop before the file
+	// queue is closed. retrySends counts retries being sent to the queue,
+	// retryStop makes them give up.
+	retryMu      sync.Mutex
+	retries      map[*time.Timer]WorkError
+	retryStopped bool
+	retryStop    chan struct{}
+	retrySends   sync.WaitGroup
+
+	// Every file that finished or gave up this run
+	run *report.Run
 
 	// Data loss monitoring
 	totalRabbitMQFailures     int
 	totalPersistedBatches     int
 	totalCircuitBreakerEvents int
 	totalMemoryPressureEvents int
+	totalBacklogEvents        int
 }
 
 type PoolMetrics struct {
 	TotalFilesProcessed   int
+	TotalFilesFailed      int // Gave up after the last retry, or quarantined
+	TotalFilesQuarantined int
 	TotalRecordsProcessed int
 	TotalBatchesProcessed int
 	TotalErrors           int
@@ -64,6 +90,7 @@ type PoolMetrics struct {
 	PersistedBatches     int
 	CircuitBreakerEvents int
 	MemoryPressureEvents int
+	BacklogEvents        int     // Batch cut because publishing fell behind
 	DataLossRate         float64 // Percentage of data that was lost vs persisted
 }
 
@@ -71,16 +98,23 @@ func NewWorkerPool(cfg *config.Config, logger *zap.Logger) *WorkerPool {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	var rabbitPool *messaging.ConnectionPool
-	var err error
 
-	if !cfg.DisableRabbitMQ {
-		rabbitPool, err = messaging.NewConnectionPool(cfg.RabbitMQURL, cfg.RabbitMQPoolSize)
+	// Only the RabbitMQ sink needs the broker connections. A broker that is
+	// down at startup is redialled in the background while batches spool.
+	if !cfg.DisableRabbitMQ && (cfg.Sink == "" || cfg.Sink == sink.KindRabbitMQ) {
+		batchSpool, err := spool.New(cfg.SpoolDirectory, cfg.SpoolMaxBytes)
 		if err != nil {
-			logger.Fatal("Failed to create RabbitMQ connection pool",
+			logger.Warn("Disk spool unavailable, failed batches will be dropped",
 				zap.Error(err),
-				zap.String("url", cfg.RabbitMQURL),
-				zap.String("action", "Verify RabbitMQ is running and accessible"))
+				zap.String("path", cfg.SpoolDirectory))
+			batchSpool = nil
 		}
+
+		rabbitPool = messaging.OpenConnectionPool(cfg, batchSpool)
+
+		// Send anything left over from earlier runs, slots that connect
+		// later replay it as they come up
+		rabbitPool.ReplaySpoolAsync()
 	}
 
 	workerMetrics := make([]WorkerMetrics, cfg.WorkerCount)
@@ -92,6 +126,8 @@ func NewWorkerPool(cfg *config.Config, logger *zap.Logger) *WorkerPool {
 		}
 	}
 
+	started := time.Now()
+
 	return &WorkerPool{
 		config:        cfg,
 		fileQueue:     make(chan WorkItem, cfg.FileQueueSize),
@@ -102,8 +138,12 @@ func NewWorkerPool(cfg *config.Config, logger *zap.Logger) *WorkerPool {
 		rabbitPool:    rabbitPool,
 		logger:        logger,
 		workerMetrics: workerMetrics,
+		activeSinks:   make([]sink.Sink, cfg.WorkerCount),
+		retries:       make(map[*time.Timer]WorkError),
+		retryStop:     make(chan struct{}),
+		run:           report.NewRun(started),
 		metrics: PoolMetrics{
-			StartTime:     time.Now(),
+			StartTime:     started,
 			WorkerMetrics: workerMetrics,
 		},
 	}
@@ -113,6 +153,41 @@ func (wp *WorkerPool) SetProgressDisplay(pd *ProgressDisplay) {
 	wp.progressDisplay = pd
 }
 
+// SetLedger records every successfully processed file in l.
+func (wp *WorkerPool) SetLedger(l *ledger.Ledger) {
+	wp.ledger = l
+}
+
+// Report returns the files finished so far, stamped with the current time
+func (wp *WorkerPool) Report() *report.Run {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.run.Finish(time.Now())
+}
+
+// SetQuarantine adds files that cannot be processed to q instead of
+// retrying them.
+func (wp *WorkerPool) SetQuarantine(q *ledger.Quarantine) {
+	wp.quarantine = q
+}
+
+// SetUnsentHandler calls fn with the path of every file that finished this
+// run without reaching the ledger or quarantine, so watch can rescan it
+func (wp *WorkerPool) SetUnsentHandler(fn func(path string)) {
+	wp.unsent = fn
+}
+
+func (wp *WorkerPool) markUnsent(path string) {
+	if wp.unsent != nil {
+		wp.unsent(path)
+	}
+}
+
+// IsQuarantined reports whether the file is quarantined and unchanged since
+func (wp *WorkerPool) IsQuarantined(path string, info os.FileInfo) bool {
+	return wp.quarantine != nil && wp.quarantine.Contains(path, info)
+}
+
 func (wp *WorkerPool) Start() error {
 	eg, ctx := errgroup.WithContext(wp.ctx)
 	wp.eg = eg
@@ -165,6 +240,8 @@ func (wp *WorkerPool) SubmitFile(item WorkItem) error {
 func (wp *WorkerPool) Stop() error {
 	wp.logger.Info("Starting graceful shutdown")
 
+	wp.cancelRetries()
+	wp.retrySends.Wait()
 	close(wp.fileQueue)
 	wp.logger.Info("File queue closed, waiting for workers to finish current files")
 
@@ -199,31 +276,78 @@ func (wp *WorkerPool) Stop() error {
 
 func (wp *WorkerPool) GetMetrics() PoolMetrics {
 	wp.mu.Lock()
-	defer wp.mu.Unlock()
-
 	metrics := wp.metrics
 	metrics.QueueDepth = len(wp.fileQueue)
 
 	metrics.WorkerMetrics = make([]WorkerMetrics, len(wp.workerMetrics))
 	copy(metrics.WorkerMetrics, wp.workerMetrics)
+	metrics.CircuitBreakerEvents = wp.totalCircuitBreakerEvents
 
 	// Copy data loss tracking metrics
 	metrics.RabbitMQFailures = wp.totalRabbitMQFailures
 	metrics.PersistedBatches = wp.totalPersistedBatches
-	metrics.CircuitBreakerEvents = wp.totalCircuitBreakerEvents
 	metrics.MemoryPressureEvents = wp.totalMemoryPressureEvents
+	metrics.BacklogEvents = wp.totalBacklogEvents
+
+	// The sinks take their own locks, some across a publish, so they are
+	// read after wp.mu is released
+	sinks := make([]sink.Sink, len(wp.activeSinks))
+	copy(sinks, wp.activeSinks)
+	wp.mu.Unlock()
+
+	for i, out := range sinks {
+		if out != nil && i < len(metrics.WorkerMetrics) {
+			readSinkMetrics(&metrics.WorkerMetrics[i], out)
+			// Trips on files still in progress are not in the total yet
+			metrics.CircuitBreakerEvents += metrics.WorkerMetrics[i].BreakerTrips
+		}
+	}
 
 	// Calculate data loss rate
-	totalBatches := metrics.TotalBatchesProcessed + wp.totalPersistedBatches
+	totalBatches := metrics.TotalBatchesProcessed + metrics.PersistedBatches
 	if totalBatches > 0 {
 		// Data loss rate = (persisted batches / total batches) * 100
 		// This represents the percentage of data that had to be persisted due to RabbitMQ failures
-		metrics.DataLossRate = (float64(wp.totalPersistedBatches) / float64(totalBatches)) * 100
+		metrics.DataLossRate = (float64(metrics.PersistedBatches) / float64(totalBatches)) * 100
 	}
 
 	return metrics
 }
 
+// displayMetrics is implemented by sinks with a publish queue (PubSub)
+type displayMetrics interface {
+	GetDisplayMetrics() map[string]interface{}
+}
+
+func readSinkMetrics(wm *WorkerMetrics, out sink.Sink) {
+	publish := out.GetMetrics()
+	wm.RecordsSent = publish.TotalRecords
+	wm.BatchesSent = publish.SentBatches
+	wm.FailedBatches = publish.FailedBatches
+	wm.PersistedBatches = publish.PersistedBatches
+	wm.CircuitBreakerOpen = publish.CircuitBreakerOpen
+	wm.BreakerState = publish.CircuitBreakerState
+	wm.BreakerTrips = publish.CircuitBreakerTrips
+	wm.BatchSize = publish.AdaptiveBatchSize
+
+	if d, ok := out.(displayMetrics); ok {
+		display := d.GetDisplayMetrics()
+		wm.PublishQueueSize, _ = display["queue_size"].(int)
+		wm.PublishQueueCap, _ = display["queue_capacity"].(int)
+	}
+}
+
+// setActiveSink tracks the sink a worker is currently writing to, nil once
+// the file is done.
+func (wp *WorkerPool) setActiveSink(workerID int, out sink.Sink) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if workerID >= 0 && workerID < len(wp.activeSinks) {
+		wp.activeSinks[workerID] = out
+	}
+}
+
 func (wp *WorkerPool) UpdateWorkerStatus(workerID int, currentFile, status string) {
 	wp.mu.Lock()
 	defer wp.mu.Unlock()
@@ -233,6 +357,12 @@ func (wp *WorkerPool) UpdateWorkerStatus(workerID int, currentFile, status strin
 		wp.workerMetrics[workerID].Status = status
 		wp.workerMetrics[workerID].LastActivity = time.Now()
 
+		busy := 0.0
+		if status != "IDLE" {
+			busy = 1
+		}
+		metrics.WorkerBusy.WithLabelValues(strconv.Itoa(workerID)).Set(busy)
+
 		if wp.progressDisplay != nil {
 			wp.progressDisplay.UpdateWorker(workerID, currentFile, status)
 		}
@@ -275,6 +405,7 @@ func (wp *WorkerPool) handleResult(result WorkResult) {
 	wp.metrics.TotalRecordsProcessed += result.ProcessedCount
 	wp.metrics.TotalBatchesProcessed += result.BatchCount
 	wp.metrics.QueueDepth--
+	wp.run.Add(result.Report)
 
 	// Update Prometheus metrics
 	metrics.FilesProcessedTotal.Inc()
@@ -287,9 +418,11 @@ func (wp *WorkerPool) handleResult(result WorkResult) {
 	if result.MessagingMetrics != nil {
 		wp.totalRabbitMQFailures += result.MessagingMetrics.FailedBatches
 		wp.totalPersistedBatches += result.MessagingMetrics.PersistedBatches
-		if result.MessagingMetrics.CircuitBreakerOpen {
-			wp.totalCircuitBreakerEvents++
-		}
+		wp.totalMemoryPressureEvents += result.MessagingMetrics.MemoryPressureEvents
+		wp.totalBacklogEvents += result.MessagingMetrics.BacklogEvents
+		metrics.PublishFailuresTotal.Add(float64(result.MessagingMetrics.FailedBatches))
+		metrics.BatchesPersistedTotal.Add(float64(result.MessagingMetrics.PersistedBatches))
+		wp.totalCircuitBreakerEvents += result.MessagingMetrics.CircuitBreakerTrips
 	}
 
 	if result.WorkerID >= 0 && result.WorkerID < len(wp.workerMetrics) {
@@ -309,6 +442,12 @@ func (wp *WorkerPool) handleResult(result WorkResult) {
 		if result.Duration.Seconds() > 0 {
 			wm.ProcessingRate = float64(result.ProcessedCount) / result.Duration.Seconds()
 		}
+
+		worker := strconv.Itoa(result.WorkerID)
+		metrics.WorkerFilesProcessed.WithLabelValues(worker).Inc()
+		metrics.WorkerRecordsProcessed.WithLabelValues(worker).Add(float64(result.ProcessedCount))
+		metrics.WorkerProcessingRate.WithLabelValues(worker).Set(wm.ProcessingRate)
+		metrics.WorkerBusy.WithLabelValues(worker).Set(0)
 	}
 
 	if wp.progressDisplay != nil {
@@ -341,9 +480,24 @@ func (wp *WorkerPool) handleError(workError WorkError) {
 	wp.mu.Lock()
 	wp.metrics.TotalErrors++
 	wp.metrics.QueueDepth--
+	if workError.WorkerID >= 0 && workError.WorkerID < len(wp.workerMetrics) {
+		wp.workerMetrics[workError.WorkerID].ErrorCount++
+	}
 	wp.mu.Unlock()
 
-	if workError.Retry && workError.RetryCount < wp.config.MaxRetries {
+	metrics.WorkerErrors.WithLabelValues(strconv.Itoa(workError.WorkerID)).Inc()
+	metrics.FileErrorsTotal.WithLabelValues(string(workError.Kind)).Inc()
+
+	switch {
+	case workError.Kind.Permanent():
+		wp.logger.Error("File cannot be processed, quarantined",
+			zap.String("file_path", workError.FilePath),
+			zap.String("kind", string(workError.Kind)),
+			zap.Error(workError.Error),
+			zap.String("action", "Fix or replace the file, then run ingest quarantine release"))
+		wp.quarantineFile(workError)
+
+	case workError.Kind.Retryable() && workError.RetryCount < wp.config.MaxRetries:
 		// Try to get FileInfo for retry
 		fileInfo, err := os.Stat(workError.FilePath)
 		if err != nil {
@@ -351,6 +505,7 @@ func (wp *WorkerPool) handleError(workError WorkError) {
 				zap.String("file_path", workError.FilePath),
 				zap.Error(err),
 				zap.String("action", "Check file exists and has read permissions"))
+			wp.recordFailure(workError, report.StatusFailed)
 			return
 		}
 
@@ -361,22 +516,147 @@ func (wp *WorkerPool) handleError(workError WorkError) {
 			FilePath:   workError.FilePath,
 			FileInfo:   dirEntry,
 			RetryCount: workError.RetryCount + 1,
+			Archive:    workError.Archive,
 		}
 
-		time.AfterFunc(wp.config.RetryDelay, func() {
-			select {
-			case wp.fileQueue <- retryItem:
-				// Retry scheduled - no log needed
-			case <-wp.ctx.Done():
-			}
-		})
-	} else {
+		delay := wp.retryDelay(workError.RetryCount)
+		wp.logger.Warn("Retrying file",
+			zap.String("file_path", workError.FilePath),
+			zap.String("kind", string(workError.Kind)),
+			zap.Int("attempt", workError.RetryCount+2),
+			zap.Duration("delay", delay),
+			zap.Error(workError.Error))
+
+		wp.scheduleRetry(workError, retryItem, delay)
+
+	default:
 		wp.logger.Error("File processing failed",
 			zap.String("file_path", workError.FilePath),
+			zap.String("kind", string(workError.Kind)),
 			zap.Int("attempts", workError.RetryCount+1),
 			zap.Error(workError.Error),
-			zap.String("action", "Check file format is valid IBT or investigate error above"))
+			zap.String("action", "The file is not in the ledger so the next run tries it again"))
+		wp.recordFailure(workError, report.StatusFailed)
+	}
+}
+
+// scheduleRetry queues the item again after delay, unless Stop cancels it
+// first. The send waits for room in the queue without holding retryMu, so
+// the error collector and Stop are not held up by a full queue. Stop waits
+// for sends in flight before it closes the queue.
+func (wp *WorkerPool) scheduleRetry(workError WorkError, item WorkItem, delay time.Duration) {
+	wp.retryMu.Lock()
+	defer wp.retryMu.Unlock()
+
+	if wp.retryStopped {
+		wp.recordFailure(workError, report.StatusFailed)
+		return
 	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		wp.retryMu.Lock()
+		if _, pending := wp.retries[timer]; !pending {
+			wp.retryMu.Unlock()
+			return
+		}
+		delete(wp.retries, timer)
+		wp.retrySends.Add(1)
+		wp.retryMu.Unlock()
+
+		defer wp.retrySends.Done()
+		select {
+		case wp.fileQueue <- item:
+			// Retry scheduled - no log needed
+		case <-wp.retryStop:
+			wp.logger.Warn("Retry cancelled by shutdown",
+				zap.String("file_path", workError.FilePath),
+				zap.String("action", "The file is not in the ledger so the next run tries it again"))
+			wp.recordFailure(workError, report.StatusFailed)
+		case <-wp.ctx.Done():
+			wp.recordFailure(workError, report.StatusFailed)
+		}
+	})
+	wp.retries[timer] = workError
+}
+
+// cancelRetries stops the pending retries and counts their files as failed,
+// retries waiting for room in the queue give up too
+func (wp *WorkerPool) cancelRetries() {
+	wp.retryMu.Lock()
+	defer wp.retryMu.Unlock()
+
+	if wp.retryStopped {
+		return
+	}
+	wp.retryStopped = true
+	close(wp.retryStop)
+
+	for timer, workError := range wp.retries {
+		timer.Stop()
+		wp.logger.Warn("Retry cancelled by shutdown",
+			zap.String("file_path", workError.FilePath),
+			zap.String("action", "The file is not in the ledger so the next run tries it again"))
+		wp.recordFailure(workError, report.StatusFailed)
+	}
+	clear(wp.retries)
+}
+
+// retryDelay doubles RETRY_DELAY with each attempt, up to RETRY_MAX_DELAY
+func (wp *WorkerPool) retryDelay(retryCount int) time.Duration {
+	delay := wp.config.RetryDelay
+	for i := 0; i < retryCount && delay < wp.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, wp.config.RetryMaxDelay)
+}
+
+// quarantineFile lists a broken file so later runs skip it
+func (wp *WorkerPool) quarantineFile(workError WorkError) {
+	wp.recordFailure(workError, report.StatusQuarantined)
+	metrics.FilesQuarantinedTotal.Inc()
+
+	if wp.quarantine == nil {
+		wp.markUnsent(workError.FilePath)
+		return
+	}
+
+	info, err := os.Stat(workError.FilePath)
+	if err == nil {
+		err = wp.quarantine.Add(workError.FilePath, info, string(workError.Kind), workError.Error.Error(), workError.RetryCount+1)
+	}
+	if err != nil {
+		wp.logger.Warn("Failed to add file to quarantine",
+			zap.String("file", workError.FilePath),
+			zap.Error(err),
+			zap.String("action", "File will be tried again on the next run"))
+		wp.markUnsent(workError.FilePath)
+	}
+}
+
+// recordFailure counts a file that will not be retried again
+func (wp *WorkerPool) recordFailure(workError WorkError, status string) {
+	if status != report.StatusQuarantined {
+		wp.markUnsent(workError.FilePath)
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	wp.metrics.TotalFilesFailed++
+	if status == report.StatusQuarantined {
+		wp.metrics.TotalFilesQuarantined++
+	}
+	wp.run.Add(report.File{
+		Path:      workError.FilePath,
+		Archive:   workError.Archive,
+		Status:    status,
+		Error:     workError.Error.Error(),
+		ErrorKind: string(workError.Kind),
+		Attempts:  workError.RetryCount + 1,
+		Started:   workError.Started,
+		Duration:  workError.Timestamp.Sub(workError.Started).Seconds(),
+	})
 }
 
 func (wp *WorkerPool) logFinalMetrics() {
@@ -397,6 +677,13 @@ func (wp *WorkerPool) logFinalMetrics() {
 		}
 	}
 
+	if wp.totalCircuitBreakerEvents > 0 {
+		wp.logger.Warn("RabbitMQ circuit breaker opened during the run",
+			zap.Int("trips", wp.totalCircuitBreakerEvents),
+			zap.Int("persisted_batches", wp.totalPersistedBatches),
+			zap.String("action", "Spooled batches are replayed once RabbitMQ is back, check the broker logs for why it stopped accepting batches"))
+	}
+
 	if wp.metrics.TotalErrors > 0 {
 		wp.logger.Error("Processing completed with errors",
 			zap.Int("total_errors", wp.metrics.TotalErrors),
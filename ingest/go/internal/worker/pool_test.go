@@ -0,0 +1,198 @@
+package worker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/config"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/ledger"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/report"
+	"go.uber.org/zap"
+)
+
+func TestRetryDelay(t *testing.T) {
+	testCases := []struct {
+		name       string
+		base       time.Duration
+		max        time.Duration
+		retryCount int
+		want       time.Duration
+	}{
+		{"FirstRetry", time.Second, time.Minute, 0, time.Second},
+		{"Doubles", time.Second, time.Minute, 1, 2 * time.Second},
+		{"DoublesAgain", time.Second, time.Minute, 3, 8 * time.Second},
+		{"CappedAtMax", time.Second, time.Minute, 10, time.Minute},
+		{"BaseAboveMax", 2 * time.Minute, time.Minute, 0, time.Minute},
+		{"BaseEqualsMax", time.Minute, time.Minute, 5, time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wp := &WorkerPool{config: &config.Config{RetryDelay: tc.base, RetryMaxDelay: tc.max}}
+
+			if got := wp.retryDelay(tc.retryCount); got != tc.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tc.retryCount, got, tc.want)
+			}
+		})
+	}
+}
+
+// newTestPool builds a pool without RabbitMQ or workers, so nothing drains
+// the file queue
+func newTestPool(t *testing.T) *WorkerPool {
+	t.Helper()
+	cfg := &config.Config{
+		DisableRabbitMQ: true,
+		FileQueueSize:   1,
+		MaxRetries:      2,
+		RetryDelay:      time.Millisecond,
+		RetryMaxDelay:   time.Millisecond,
+	}
+	return NewWorkerPool(cfg, zap.NewNop())
+}
+
+func writeIBT(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.ibt")
+	if err := os.WriteFile(path, []byte("telemetry"), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestHandleError(t *testing.T) {
+	testCases := []struct {
+		name            string
+		kind            processing.ErrorKind
+		retryCount      int
+		missing         bool
+		wantStatus      string
+		wantQuarantined bool
+		wantRetry       bool
+	}{
+		{"PermanentQuarantined", processing.KindParse, 0, false, report.StatusQuarantined, true, false},
+		{"BadFileQuarantined", processing.KindBadFile, 0, false, report.StatusQuarantined, true, false},
+		{"RetryableRetried", processing.KindBroker, 0, false, "", false, true},
+		{"RetriesExhausted", processing.KindBroker, 2, false, report.StatusFailed, false, false},
+		{"CancelledNotRetried", processing.KindCancelled, 0, false, report.StatusFailed, false, false},
+		{"MissingFile", processing.KindIO, 0, true, report.StatusFailed, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wp := newTestPool(t)
+			q, err := ledger.OpenQuarantine(filepath.Join(t.TempDir(), "quarantine.json"))
+			if err != nil {
+				t.Fatalf("Failed to open quarantine: %v", err)
+			}
+			wp.SetQuarantine(q)
+
+			path := writeIBT(t)
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Failed to stat %s: %v", path, err)
+			}
+			if tc.missing {
+				os.Remove(path)
+			}
+
+			wp.handleError(WorkError{FilePath: path, Error: errors.New("boom"), Kind: tc.kind, RetryCount: tc.retryCount})
+
+			if tc.wantRetry {
+				select {
+				case item := <-wp.fileQueue:
+					if item.FilePath != path || item.RetryCount != tc.retryCount+1 {
+						t.Errorf("queued %+v, want %s at retry %d", item, path, tc.retryCount+1)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("file was not queued again")
+				}
+			}
+
+			run := wp.Report()
+			if tc.wantStatus == "" {
+				if len(run.Reports) != 0 {
+					t.Errorf("retried file was reported as %s", run.Reports[0].Status)
+				}
+			} else if len(run.Reports) != 1 || run.Reports[0].Status != tc.wantStatus {
+				t.Errorf("reports = %+v, want one %s", run.Reports, tc.wantStatus)
+			}
+			if got := q.Contains(path, info); got != tc.wantQuarantined {
+				t.Errorf("quarantined = %v, want %v", got, tc.wantQuarantined)
+			}
+		})
+	}
+}
+
+func TestStopWithRetryWaitingOnFullQueue(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Stop waits for workers to finish")
+	}
+
+	wp := newTestPool(t)
+	wp.config.RetryDelay, wp.config.RetryMaxDelay = 20*time.Millisecond, 20*time.Millisecond
+	if err := wp.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Fill the queue so the retry has nowhere to go
+	if err := wp.SubmitFile(WorkItem{FilePath: "queued.ibt"}); err != nil {
+		t.Fatalf("SubmitFile failed: %v", err)
+	}
+
+	path := writeIBT(t)
+	wp.errorsChan <- WorkError{FilePath: path, Error: errors.New("broker down"), Kind: processing.KindBroker}
+
+	// Wait for the retry to be scheduled, then for its timer to fire and
+	// block on the full queue
+	retries := func() int {
+		wp.retryMu.Lock()
+		defer wp.retryMu.Unlock()
+		return len(wp.retries)
+	}
+	for _, want := range []int{1, 0} {
+		deadline := time.Now().Add(time.Second)
+		for retries() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("pending retries did not reach %d", want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// The error collector must still take errors while the retry waits
+	second := writeIBT(t)
+	select {
+	case wp.errorsChan <- WorkError{FilePath: second, Error: errors.New("bad header"), Kind: processing.KindParse}:
+	case <-time.After(time.Second):
+		t.Fatalf("error collector is blocked behind the retry")
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- wp.Stop() }()
+	select {
+	case <-stopped:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("Stop did not return with a retry waiting on a full queue")
+	}
+
+	metrics := wp.GetMetrics()
+	if metrics.TotalFilesFailed != 2 {
+		t.Errorf("TotalFilesFailed = %d, want 2", metrics.TotalFilesFailed)
+	}
+	run := wp.Report()
+	statuses := map[string]string{}
+	for _, file := range run.Reports {
+		statuses[file.Path] = file.Status
+	}
+	if statuses[path] != report.StatusFailed {
+		t.Errorf("retried file status = %q, want failed", statuses[path])
+	}
+	if statuses[second] != report.StatusQuarantined {
+		t.Errorf("broken file status = %q, want quarantined", statuses[second])
+	}
+}
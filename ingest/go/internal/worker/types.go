@@ -5,12 +5,15 @@ import (
 	"time"
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/messaging"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/report"
 )
 
 type WorkItem struct {
 	FilePath   string
 	FileInfo   os.DirEntry
 	RetryCount int
+	Archive    string // Bundle the file was extracted from, if any
 }
 
 type WorkResult struct {
@@ -22,15 +25,18 @@ type WorkResult struct {
 	TrackName        string
 	WorkerID         int
 	MessagingMetrics *messaging.PublishMetrics
+	Report           report.File
 }
 
 type WorkError struct {
 	FilePath   string
+	Archive    string
 	Error      error
-	Retry      bool
+	Kind       processing.ErrorKind // Decides between retry and quarantine
 	WorkerID   int
 	RetryCount int
 	Timestamp  time.Time
+	Started    time.Time
 }
 
 type WorkerMetrics struct {
@@ -46,4 +52,16 @@ type WorkerMetrics struct {
 	ProcessingRate   float64
 	AvgTimePerFile   time.Duration
 	TotalFileTime    time.Duration
+
+	// Live publish state of the sink the worker is writing to
+	RecordsSent        int
+	BatchesSent        int
+	PublishQueueSize   int
+	PublishQueueCap    int
+	BatchSize          int
+	FailedBatches      int
+	PersistedBatches   int
+	CircuitBreakerOpen bool
+	BreakerState       string
+	BreakerTrips       int
 }
@@ -7,11 +7,21 @@ import (
 	"time"
 
 	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/processing"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/report"
+	"github.com/OJPARKINSON/IRacing-Display/ingest/go/internal/sink"
 	"go.uber.org/zap"
 )
 
 func (wp *WorkerPool) startWorker(workerID int) {
-	workerCtx, cancel := context.WithTimeout(wp.ctx, wp.config.WorkerTimeout)
+	// A zero WorkerTimeout keeps the worker alive until the pool is stopped,
+	// which is what long running watch sessions need.
+	var workerCtx context.Context
+	var cancel context.CancelFunc
+	if wp.config.WorkerTimeout > 0 {
+		workerCtx, cancel = context.WithTimeout(wp.ctx, wp.config.WorkerTimeout)
+	} else {
+		workerCtx, cancel = context.WithCancel(wp.ctx)
+	}
 	defer cancel()
 
 	for {
@@ -38,11 +48,13 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 		wp.logger.Error("Worker FileInfo is nil", zap.Int("worker_id", workerID), zap.String("file_path", item.FilePath))
 		wp.errorsChan <- WorkError{
 			FilePath:   item.FilePath,
+			Archive:    item.Archive,
 			Error:      fmt.Errorf("FileInfo is nil"),
-			Retry:      false,
+			Kind:       processing.KindBadFile,
 			WorkerID:   workerID,
 			RetryCount: item.RetryCount,
 			Timestamp:  time.Now(),
+			Started:    startTime,
 		}
 		return
 	}
@@ -60,11 +72,13 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 		wp.UpdateWorkerStatus(workerID, filename, "ERROR")
 		wp.errorsChan <- WorkError{
 			FilePath:   item.FilePath,
+			Archive:    item.Archive,
 			Error:      err,
-			Retry:      true,
+			Kind:       processing.Kind(err),
 			WorkerID:   workerID,
 			RetryCount: item.RetryCount,
 			Timestamp:  time.Now(),
+			Started:    startTime,
 		}
 		return
 	}
@@ -75,6 +89,16 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 		processor.SetProgressCallback(wp.progressDisplay)
 	}
 
+	// Track each group's sink so the dashboard can show its publish queue
+	processor.SetSinkFactory(func(sessionID, trackName string, sessionTime time.Time) (sink.Sink, error) {
+		out, err := sink.New(wp.config, wp.rabbitPool, sessionID, sessionTime, workerID)
+		if err == nil {
+			wp.setActiveSink(workerID, out)
+		}
+		return out, err
+	})
+	defer wp.setActiveSink(workerID, nil)
+
 	processCtx, processCancel := context.WithTimeout(ctx, wp.config.FileProcessTimeout)
 	defer processCancel()
 
@@ -100,7 +124,10 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 		processErr = res.err
 	case <-processCtx.Done():
 		if processCtx.Err() == context.DeadlineExceeded {
-			processErr = fmt.Errorf("file processing timeout after %v", wp.config.FileProcessTimeout)
+			processErr = &processing.FileError{
+				Kind: processing.KindTimeout,
+				Err:  fmt.Errorf("file processing timeout after %v", wp.config.FileProcessTimeout),
+			}
 			wp.logger.Error("File processing timeout",
 				zap.String("file", item.FilePath),
 				zap.Duration("timeout", wp.config.FileProcessTimeout),
@@ -113,7 +140,10 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 					zap.Error(flushErr),
 					zap.String("action", "Some data may be lost - check disk persistence directory"))
 			}
-			processErr = fmt.Errorf("processing cancelled due to graceful shutdown")
+			processErr = &processing.FileError{
+				Kind: processing.KindCancelled,
+				Err:  fmt.Errorf("processing cancelled due to graceful shutdown"),
+			}
 		}
 	}
 
@@ -121,15 +151,20 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 		wp.UpdateWorkerStatus(workerID, filename, "ERROR")
 		wp.errorsChan <- WorkError{
 			FilePath:   item.FilePath,
+			Archive:    item.Archive,
 			Error:      processErr,
-			Retry:      shouldRetry(processErr, item.RetryCount),
+			Kind:       processing.Kind(processErr),
 			WorkerID:   workerID,
 			RetryCount: item.RetryCount,
 			Timestamp:  time.Now(),
+			Started:    startTime,
 		}
 		return
 	}
 
+	fileReport := newFileReport(item, result, startTime)
+	wp.recordInLedger(item, &fileReport)
+
 	wp.resultsChan <- WorkResult{
 		FilePath:         item.FilePath,
 		ProcessedCount:   result.RecordCount,
@@ -139,17 +174,63 @@ func (wp *WorkerPool) processWorkItem(ctx context.Context, workerID int, item Wo
 		TrackName:        result.TrackName,
 		WorkerID:         workerID,
 		MessagingMetrics: result.MessagingMetrics,
+		Report:           fileReport,
 	}
 }
 
-func shouldRetry(err error, retryCount int) bool {
-	if retryCount >= 3 {
-		return false
+func newFileReport(item WorkItem, result *processing.ProcessResult, startTime time.Time) report.File {
+	fileReport := report.File{
+		Path:     item.FilePath,
+		Archive:  item.Archive,
+		Status:   report.StatusIngested,
+		Attempts: item.RetryCount + 1,
+		Sessions: result.Sessions,
+		Track:    result.TrackName,
+		Laps:     result.Laps,
+		Records:  result.RecordCount,
+		Batches:  result.BatchCount,
+		Started:  startTime,
+		Duration: time.Since(startTime).Seconds(),
 	}
 
-	if err == context.Canceled || err == context.DeadlineExceeded {
-		return false
+	if m := result.MessagingMetrics; m != nil {
+		fileReport.SentBatches = m.SentBatches
+		fileReport.Bytes = m.TotalBytes
+		fileReport.FailedBatches = m.FailedBatches
+		fileReport.PersistedBatches = m.PersistedBatches
+	}
+	fileReport.Status = report.SinkStatus(fileReport.Batches, fileReport.SentBatches, fileReport.FailedBatches, fileReport.PersistedBatches)
+	return fileReport
+}
+
+// recordInLedger marks a successfully processed file so the next run skips it.
+// Files with batches that failed or are waiting in the spool are left out
+// so the next run sends them again.
+func (wp *WorkerPool) recordInLedger(item WorkItem, fileReport *report.File) {
+	if wp.ledger == nil {
+		return
 	}
 
-	return true
+	if fileReport.Status != report.StatusIngested {
+		wp.logger.Warn("File not recorded in ledger, some batches did not reach the server",
+			zap.String("file", item.FilePath),
+			zap.String("status", fileReport.Status),
+			zap.Int("failed_batches", fileReport.FailedBatches),
+			zap.Int("persisted_batches", fileReport.PersistedBatches),
+			zap.String("action", "File will be sent again on the next run"))
+		wp.markUnsent(item.FilePath)
+		return
+	}
+
+	info, err := item.FileInfo.Info()
+	if err == nil {
+		err = wp.ledger.Record(item.FilePath, info, fileReport)
+	}
+	if err != nil {
+		wp.logger.Warn("Failed to record file in ledger",
+			zap.String("file", item.FilePath),
+			zap.Error(err),
+			zap.String("action", "File will be sent again on the next run"))
+		wp.markUnsent(item.FilePath)
+	}
 }
@@ -26,8 +26,31 @@ func main() {
 		log.Println("Exiting due to database initialization failure")
 		os.Exit(1)
 	}
+	if err := schema.CreateCarTableHTTP(); err != nil {
+		log.Printf("Failed to create car table: %v", err)
+		log.Println("Exiting due to database initialization failure")
+		os.Exit(1)
+	}
+	if err := schema.CreateSessionTableHTTP(); err != nil {
+		log.Printf("Failed to create session metadata table: %v", err)
+		log.Println("Exiting due to database initialization failure")
+		os.Exit(1)
+	}
+	if err := schema.CreateAppliedBatchTableHTTP(); err != nil {
+		log.Printf("Failed to create applied batch table: %v", err)
+		log.Println("Exiting due to database initialization failure")
+		os.Exit(1)
+	}
 	log.Println("Database schema initialized successfully")
 
+	// Without the record a re-ingest falls back to QuestDB's DEDUP keys
+	applied, err := persistance.LoadAppliedBatches(config)
+	if err != nil {
+		log.Printf("Starting without previously applied batches: %v", err)
+	} else {
+		log.Printf("Loaded %d applied batch IDs", applied.Len())
+	}
+
 	apiServer := api.NewServer(":8010", &persistance.QueryExecutor{
 		Config: config,
 	})
@@ -53,7 +76,7 @@ func main() {
 	log.Println("Starting to consume messages from RabbitMQ")
 
 	// Start message queue subscriber
-	messaging := queue.NewSubscriber(senderPool)
+	messaging := queue.NewSubscriber(senderPool, applied)
 	go func() {
 		messaging.Subscribe(config)
 	}()
@@ -8,14 +8,21 @@ import (
 	"net/http"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/ojparkinson/telemetryService/internal/geojson"
+	"github.com/ojparkinson/telemetryService/internal/persistance"
 	"github.com/ojparkinson/telemetryService/internal/sync"
 )
 
-// /api/sessions
+// /api/sessions?type=practice
 func (s *Server) handleGetSessions(w http.ResponseWriter, r *http.Request) {
-	sessions, err := s.queryExecutor.QuerySessions(r.Context())
+	filter, ok := sessionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := s.queryExecutor.QuerySessions(r.Context(), filter)
 	if err != nil {
 		log.Println(err)
 		respondError(w, http.StatusInternalServerError, "Failed to fetch sessions")
@@ -25,7 +32,7 @@ func (s *Server) handleGetSessions(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, 200, sessions)
 }
 
-// /api/sessions/123456/laps
+// /api/sessions/123456/laps?session_num=0
 func (s *Server) handleGetLaps(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("sessionId")
 	if sessionID == "" {
@@ -33,7 +40,12 @@ func (s *Server) handleGetLaps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := s.queryExecutor.QueryLaps(r.Context(), sessionID)
+	filter, ok := sessionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := s.queryExecutor.QueryLaps(r.Context(), sessionID, filter)
 	if err != nil {
 		log.Println(err)
 		respondError(w, http.StatusInternalServerError, "Failed to fetch laps")
@@ -50,7 +62,7 @@ func (s *Server) handleGetLaps(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, 200, laps)
 }
 
-// /api/sessions/123456/laps/1
+// /api/sessions/123456/laps/1?session_num=0
 func (s *Server) handleGetTelemetry(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.PathValue("sessionId")
 	lapID := r.PathValue("lapId")
@@ -59,7 +71,12 @@ func (s *Server) handleGetTelemetry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	lapData, err := s.queryExecutor.QueryLap(r.Context(), sessionID, lapID)
+	filter, ok := sessionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	lapData, err := s.queryExecutor.QueryLap(r.Context(), sessionID, lapID, filter)
 	if err != nil {
 		log.Println(err)
 		respondError(w, http.StatusInternalServerError, "Failed to fetch lap data")
@@ -76,7 +93,12 @@ func (s *Server) handleGetTelemetryGeoJson(w http.ResponseWriter, r *http.Reques
 
 	options := geojson.ConversionOptions{}
 
-	lapData, err := s.queryExecutor.QueryLap(r.Context(), sessionID, lapID)
+	filter, ok := sessionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	lapData, err := s.queryExecutor.QueryLap(r.Context(), sessionID, lapID, filter)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch lap data")
 		return
@@ -113,3 +135,21 @@ func (s *Server) handleSyncLap(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(200)
 }
+
+// sessionFilter reads the optional type and session_num query parameters.
+// Without them every session type is returned, not just races.
+func sessionFilter(w http.ResponseWriter, r *http.Request) (persistance.SessionFilter, bool) {
+	filter := persistance.SessionFilter{
+		SessionType: strings.TrimSpace(r.URL.Query().Get("type")),
+		SessionNum:  r.URL.Query().Get("session_num"),
+	}
+
+	if filter.SessionNum != "" {
+		if _, err := strconv.Atoi(filter.SessionNum); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid session_num")
+			return filter, false
+		}
+	}
+
+	return filter, true
+}
@@ -9,6 +9,8 @@ import (
 type Session struct {
 	SessionID   string    `json:"session_id"`
 	TrackName   string    `json:"track_name"`
+	SessionNum  string    `json:"session_num"`
+	SessionType string    `json:"session_type"`
 	SessionName string    `json:"session_name"`
 	MaxLapID    int       `json:"max_lap_id"`
 	LastUpdated time.Time `json:"last_updated"`
@@ -11,6 +11,9 @@ type Config struct {
 	QuestDBPort   int
 	QuestPoolSize int
 	RabbitMQHost  string
+
+	// How far back applied batch IDs are loaded on startup
+	AppliedBatchDays int
 }
 
 func NewConfig() *Config {
@@ -23,6 +26,8 @@ func NewConfig() *Config {
 		QuestDBPort:   questdbPort,
 		QuestPoolSize: getSenderPool(),
 		RabbitMQHost:  rabbitMqHost,
+
+		AppliedBatchDays: getEnvInt("APPLIED_BATCH_DAYS", 30),
 	}
 }
 
@@ -70,8 +70,10 @@ type Telemetry struct {
 	LRtempM            float64                `protobuf:"fixed64,44,opt,name=l_rtemp_m,json=lRtempM,proto3" json:"l_rtemp_m,omitempty"`
 	RRtempM            float64                `protobuf:"fixed64,45,opt,name=r_rtemp_m,json=rRtempM,proto3" json:"r_rtemp_m,omitempty"`
 	TickTime           *timestamppb.Timestamp `protobuf:"bytes,46,opt,name=tick_time,json=tickTime,proto3" json:"tick_time,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// Extra IBT channels picked in the channel manifest, keyed by IBT variable name
+	Channels      map[string]float64 `protobuf:"bytes,47,rep,name=channels,proto3" json:"channels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Telemetry) Reset() {
@@ -426,6 +428,13 @@ func (x *Telemetry) GetTickTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Telemetry) GetChannels() map[string]float64 {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
 type TelemetryBatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Records       []*Telemetry           `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
@@ -502,81 +511,942 @@ func (x *TelemetryBatch) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
-var File_telemetryTick_proto protoreflect.FileDescriptor
+// CarTelemetry is one car's state from the CarIdx arrays, covering the whole
+// field rather than just the player car.
+type CarTelemetry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	SessionNum    string                 `protobuf:"bytes,2,opt,name=session_num,json=sessionNum,proto3" json:"session_num,omitempty"`
+	CarIdx        uint32                 `protobuf:"varint,3,opt,name=car_idx,json=carIdx,proto3" json:"car_idx,omitempty"`
+	Lap           int32                  `protobuf:"varint,4,opt,name=lap,proto3" json:"lap,omitempty"`
+	LapDistPct    float64                `protobuf:"fixed64,5,opt,name=lap_dist_pct,json=lapDistPct,proto3" json:"lap_dist_pct,omitempty"`
+	Position      int32                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	ClassPosition int32                  `protobuf:"varint,7,opt,name=class_position,json=classPosition,proto3" json:"class_position,omitempty"`
+	OnPitRoad     bool                   `protobuf:"varint,8,opt,name=on_pit_road,json=onPitRoad,proto3" json:"on_pit_road,omitempty"`
+	TrackSurface  int32                  `protobuf:"varint,9,opt,name=track_surface,json=trackSurface,proto3" json:"track_surface,omitempty"`
+	EstTime       float64                `protobuf:"fixed64,10,opt,name=est_time,json=estTime,proto3" json:"est_time,omitempty"`
+	F2Time        float64                `protobuf:"fixed64,11,opt,name=f2_time,json=f2Time,proto3" json:"f2_time,omitempty"`
+	SessionTime   float64                `protobuf:"fixed64,12,opt,name=session_time,json=sessionTime,proto3" json:"session_time,omitempty"`
+	TickTime      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=tick_time,json=tickTime,proto3" json:"tick_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_telemetryTick_proto_rawDesc = "" +
-	"\n" +
-	"\x13telemetryTick.proto\x12\x06pubSub\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\v\n" +
-	"\tTelemetry\x12\x15\n" +
-	"\x06lap_id\x18\x01 \x01(\tR\x05lapId\x12\x14\n" +
-	"\x05speed\x18\x02 \x01(\x01R\x05speed\x12 \n" +
-	"\flap_dist_pct\x18\x03 \x01(\x01R\n" +
-	"lapDistPct\x12\x1d\n" +
-	"\n" +
-	"session_id\x18\x04 \x01(\tR\tsessionId\x12\x1f\n" +
-	"\vsession_num\x18\x05 \x01(\tR\n" +
-	"sessionNum\x12!\n" +
-	"\fsession_type\x18\x06 \x01(\tR\vsessionType\x12!\n" +
-	"\fsession_name\x18\a \x01(\tR\vsessionName\x12!\n" +
-	"\fsession_time\x18\b \x01(\x01R\vsessionTime\x12\x15\n" +
-	"\x06car_id\x18\t \x01(\tR\x05carId\x12\x1d\n" +
-	"\n" +
-	"track_name\x18\n" +
-	" \x01(\tR\ttrackName\x12\x19\n" +
-	"\btrack_id\x18\v \x01(\tR\atrackId\x12\x1b\n" +
-	"\tworker_id\x18\f \x01(\rR\bworkerId\x120\n" +
-	"\x14steering_wheel_angle\x18\r \x01(\x01R\x12steeringWheelAngle\x12.\n" +
-	"\x13player_car_position\x18\x0e \x01(\rR\x11playerCarPosition\x12\x1d\n" +
-	"\n" +
-	"velocity_x\x18\x0f \x01(\x01R\tvelocityX\x12\x1d\n" +
-	"\n" +
-	"velocity_y\x18\x10 \x01(\x01R\tvelocityY\x12\x1d\n" +
-	"\n" +
-	"velocity_z\x18\x11 \x01(\x01R\tvelocityZ\x12\x1d\n" +
-	"\n" +
-	"fuel_level\x18\x12 \x01(\x01R\tfuelLevel\x12\x1a\n" +
-	"\bthrottle\x18\x13 \x01(\x01R\bthrottle\x12\x14\n" +
-	"\x05brake\x18\x14 \x01(\x01R\x05brake\x12\x10\n" +
-	"\x03rpm\x18\x15 \x01(\x01R\x03rpm\x12\x10\n" +
-	"\x03lat\x18\x16 \x01(\x01R\x03lat\x12\x10\n" +
-	"\x03lon\x18\x17 \x01(\x01R\x03lon\x12\x12\n" +
-	"\x04gear\x18\x18 \x01(\rR\x04gear\x12\x10\n" +
-	"\x03alt\x18\x19 \x01(\x01R\x03alt\x12\x1b\n" +
-	"\tlat_accel\x18\x1a \x01(\x01R\blatAccel\x12\x1d\n" +
-	"\n" +
-	"long_accel\x18\x1b \x01(\x01R\tlongAccel\x12\x1d\n" +
-	"\n" +
-	"vert_accel\x18\x1c \x01(\x01R\tvertAccel\x12\x14\n" +
-	"\x05pitch\x18\x1d \x01(\x01R\x05pitch\x12\x12\n" +
-	"\x04roll\x18\x1e \x01(\x01R\x04roll\x12\x10\n" +
-	"\x03yaw\x18\x1f \x01(\x01R\x03yaw\x12\x1b\n" +
-	"\tyaw_north\x18  \x01(\x01R\byawNorth\x12\x18\n" +
-	"\avoltage\x18! \x01(\x01R\avoltage\x12)\n" +
-	"\x11lap_last_lap_time\x18\" \x01(\x01R\x0elapLastLapTime\x12\x1d\n" +
-	"\n" +
-	"water_temp\x18# \x01(\x01R\twaterTemp\x120\n" +
-	"\x15lap_delta_to_best_lap\x18$ \x01(\x01R\x11lapDeltaToBestLap\x12/\n" +
-	"\x14lap_current_lap_time\x18% \x01(\x01R\x11lapCurrentLapTime\x12\x1f\n" +
-	"\vl_fpressure\x18& \x01(\x01R\n" +
-	"lFpressure\x12\x1f\n" +
-	"\vr_fpressure\x18' \x01(\x01R\n" +
-	"rFpressure\x12\x1f\n" +
-	"\vl_rpressure\x18( \x01(\x01R\n" +
-	"lRpressure\x12\x1f\n" +
-	"\vr_rpressure\x18) \x01(\x01R\n" +
-	"rRpressure\x12\x1a\n" +
-	"\tl_ftemp_m\x18* \x01(\x01R\alFtempM\x12\x1a\n" +
-	"\tr_ftemp_m\x18+ \x01(\x01R\arFtempM\x12\x1a\n" +
-	"\tl_rtemp_m\x18, \x01(\x01R\alRtempM\x12\x1a\n" +
-	"\tr_rtemp_m\x18- \x01(\x01R\arRtempM\x127\n" +
-	"\ttick_time\x18. \x01(\v2\x1a.google.protobuf.TimestampR\btickTime\"\xce\x01\n" +
-	"\x0eTelemetryBatch\x12+\n" +
-	"\arecords\x18\x01 \x03(\v2\x11.pubSub.TelemetryR\arecords\x12\x19\n" +
-	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12\x1d\n" +
-	"\n" +
-	"session_id\x18\x03 \x01(\tR\tsessionId\x12\x1b\n" +
-	"\tworker_id\x18\x04 \x01(\rR\bworkerId\x128\n" +
-	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestampBSZQgithub.com/OJPARKINSON/IRacing-Display/telemetryService/golang/internal/messagingb\x06proto3"
+func (x *CarTelemetry) Reset() {
+	*x = CarTelemetry{}
+	mi := &file_telemetryTick_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarTelemetry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarTelemetry) ProtoMessage() {}
+
+func (x *CarTelemetry) ProtoReflect() protoreflect.Message {
+	mi := &file_telemetryTick_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarTelemetry.ProtoReflect.Descriptor instead.
+func (*CarTelemetry) Descriptor() ([]byte, []int) {
+	return file_telemetryTick_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CarTelemetry) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CarTelemetry) GetSessionNum() string {
+	if x != nil {
+		return x.SessionNum
+	}
+	return ""
+}
+
+func (x *CarTelemetry) GetCarIdx() uint32 {
+	if x != nil {
+		return x.CarIdx
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetLap() int32 {
+	if x != nil {
+		return x.Lap
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetLapDistPct() float64 {
+	if x != nil {
+		return x.LapDistPct
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetClassPosition() int32 {
+	if x != nil {
+		return x.ClassPosition
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetOnPitRoad() bool {
+	if x != nil {
+		return x.OnPitRoad
+	}
+	return false
+}
+
+func (x *CarTelemetry) GetTrackSurface() int32 {
+	if x != nil {
+		return x.TrackSurface
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetEstTime() float64 {
+	if x != nil {
+		return x.EstTime
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetF2Time() float64 {
+	if x != nil {
+		return x.F2Time
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetSessionTime() float64 {
+	if x != nil {
+		return x.SessionTime
+	}
+	return 0
+}
+
+func (x *CarTelemetry) GetTickTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TickTime
+	}
+	return nil
+}
+
+type CarTelemetryBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*CarTelemetry        `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	BatchId       string                 `protobuf:"bytes,2,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	WorkerId      uint32                 `protobuf:"varint,4,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarTelemetryBatch) Reset() {
+	*x = CarTelemetryBatch{}
+	mi := &file_telemetryTick_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarTelemetryBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarTelemetryBatch) ProtoMessage() {}
+
+func (x *CarTelemetryBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_telemetryTick_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarTelemetryBatch.ProtoReflect.Descriptor instead.
+func (*CarTelemetryBatch) Descriptor() ([]byte, []int) {
+	return file_telemetryTick_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CarTelemetryBatch) GetRecords() []*CarTelemetry {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *CarTelemetryBatch) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *CarTelemetryBatch) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CarTelemetryBatch) GetWorkerId() uint32 {
+	if x != nil {
+		return x.WorkerId
+	}
+	return 0
+}
+
+func (x *CarTelemetryBatch) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// SessionMetadata carries the session YAML for one group: the weekend,
+// conditions, driver roster and the player's car and setup. It is published
+// once per group, ahead of the telemetry.
+type SessionMetadata struct {
+	state        protoimpl.MessageState     `protogen:"open.v1"`
+	BatchId      string                     `protobuf:"bytes,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	SessionId    string                     `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	WorkerId     uint32                     `protobuf:"varint,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	SessionStart *timestamppb.Timestamp     `protobuf:"bytes,4,opt,name=session_start,json=sessionStart,proto3" json:"session_start,omitempty"`
+	TrackName    string                     `protobuf:"bytes,5,opt,name=track_name,json=trackName,proto3" json:"track_name,omitempty"`
+	TrackId      string                     `protobuf:"bytes,6,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	TrackConfig  string                     `protobuf:"bytes,7,opt,name=track_config,json=trackConfig,proto3" json:"track_config,omitempty"`
+	TrackCity    string                     `protobuf:"bytes,8,opt,name=track_city,json=trackCity,proto3" json:"track_city,omitempty"`
+	TrackCountry string                     `protobuf:"bytes,9,opt,name=track_country,json=trackCountry,proto3" json:"track_country,omitempty"`
+	TrackLength  string                     `protobuf:"bytes,10,opt,name=track_length,json=trackLength,proto3" json:"track_length,omitempty"`
+	SeriesId     string                     `protobuf:"bytes,11,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	SeasonId     string                     `protobuf:"bytes,12,opt,name=season_id,json=seasonId,proto3" json:"season_id,omitempty"`
+	EventType    string                     `protobuf:"bytes,13,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Category     string                     `protobuf:"bytes,14,opt,name=category,proto3" json:"category,omitempty"`
+	Weather      *SessionMetadata_Weather   `protobuf:"bytes,15,opt,name=weather,proto3" json:"weather,omitempty"`
+	Sessions     []*SessionMetadata_Session `protobuf:"bytes,16,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	Drivers      []*SessionMetadata_Driver  `protobuf:"bytes,17,rep,name=drivers,proto3" json:"drivers,omitempty"`
+	PlayerCarIdx uint32                     `protobuf:"varint,18,opt,name=player_car_idx,json=playerCarIdx,proto3" json:"player_car_idx,omitempty"`
+	CarName      string                     `protobuf:"bytes,19,opt,name=car_name,json=carName,proto3" json:"car_name,omitempty"`
+	CarPath      string                     `protobuf:"bytes,20,opt,name=car_path,json=carPath,proto3" json:"car_path,omitempty"`
+	SetupName    string                     `protobuf:"bytes,21,opt,name=setup_name,json=setupName,proto3" json:"setup_name,omitempty"`
+	// CarSetup section of the session YAML, encoded as JSON
+	CarSetup      string `protobuf:"bytes,22,opt,name=car_setup,json=carSetup,proto3" json:"car_setup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionMetadata) Reset() {
+	*x = SessionMetadata{}
+	mi := &file_telemetryTick_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMetadata) ProtoMessage() {}
+
+func (x *SessionMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_telemetryTick_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMetadata.ProtoReflect.Descriptor instead.
+func (*SessionMetadata) Descriptor() ([]byte, []int) {
+	return file_telemetryTick_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SessionMetadata) GetBatchId() string {
+	if x != nil {
+		return x.BatchId
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetWorkerId() uint32 {
+	if x != nil {
+		return x.WorkerId
+	}
+	return 0
+}
+
+func (x *SessionMetadata) GetSessionStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SessionStart
+	}
+	return nil
+}
+
+func (x *SessionMetadata) GetTrackName() string {
+	if x != nil {
+		return x.TrackName
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetTrackId() string {
+	if x != nil {
+		return x.TrackId
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetTrackConfig() string {
+	if x != nil {
+		return x.TrackConfig
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetTrackCity() string {
+	if x != nil {
+		return x.TrackCity
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetTrackCountry() string {
+	if x != nil {
+		return x.TrackCountry
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetTrackLength() string {
+	if x != nil {
+		return x.TrackLength
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetSeriesId() string {
+	if x != nil {
+		return x.SeriesId
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetSeasonId() string {
+	if x != nil {
+		return x.SeasonId
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetWeather() *SessionMetadata_Weather {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *SessionMetadata) GetSessions() []*SessionMetadata_Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *SessionMetadata) GetDrivers() []*SessionMetadata_Driver {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
+func (x *SessionMetadata) GetPlayerCarIdx() uint32 {
+	if x != nil {
+		return x.PlayerCarIdx
+	}
+	return 0
+}
+
+func (x *SessionMetadata) GetCarName() string {
+	if x != nil {
+		return x.CarName
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetCarPath() string {
+	if x != nil {
+		return x.CarPath
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetSetupName() string {
+	if x != nil {
+		return x.SetupName
+	}
+	return ""
+}
+
+func (x *SessionMetadata) GetCarSetup() string {
+	if x != nil {
+		return x.CarSetup
+	}
+	return ""
+}
+
+type SessionMetadata_Weather struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Type             string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Skies            string                 `protobuf:"bytes,2,opt,name=skies,proto3" json:"skies,omitempty"`
+	TrackTemp        string                 `protobuf:"bytes,3,opt,name=track_temp,json=trackTemp,proto3" json:"track_temp,omitempty"`
+	AirTemp          string                 `protobuf:"bytes,4,opt,name=air_temp,json=airTemp,proto3" json:"air_temp,omitempty"`
+	AirPressure      string                 `protobuf:"bytes,5,opt,name=air_pressure,json=airPressure,proto3" json:"air_pressure,omitempty"`
+	WindSpeed        string                 `protobuf:"bytes,6,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindDir          string                 `protobuf:"bytes,7,opt,name=wind_dir,json=windDir,proto3" json:"wind_dir,omitempty"`
+	RelativeHumidity string                 `protobuf:"bytes,8,opt,name=relative_humidity,json=relativeHumidity,proto3" json:"relative_humidity,omitempty"`
+	FogLevel         string                 `protobuf:"bytes,9,opt,name=fog_level,json=fogLevel,proto3" json:"fog_level,omitempty"`
+	TimeOfDay        string                 `protobuf:"bytes,10,opt,name=time_of_day,json=timeOfDay,proto3" json:"time_of_day,omitempty"`
+	Date             string                 `protobuf:"bytes,11,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SessionMetadata_Weather) Reset() {
+	*x = SessionMetadata_Weather{}
+	mi := &file_telemetryTick_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMetadata_Weather) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMetadata_Weather) ProtoMessage() {}
+
+func (x *SessionMetadata_Weather) ProtoReflect() protoreflect.Message {
+	mi := &file_telemetryTick_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMetadata_Weather.ProtoReflect.Descriptor instead.
+func (*SessionMetadata_Weather) Descriptor() ([]byte, []int) {
+	return file_telemetryTick_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *SessionMetadata_Weather) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetSkies() string {
+	if x != nil {
+		return x.Skies
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetTrackTemp() string {
+	if x != nil {
+		return x.TrackTemp
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetAirTemp() string {
+	if x != nil {
+		return x.AirTemp
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetAirPressure() string {
+	if x != nil {
+		return x.AirPressure
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetWindSpeed() string {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetWindDir() string {
+	if x != nil {
+		return x.WindDir
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetRelativeHumidity() string {
+	if x != nil {
+		return x.RelativeHumidity
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetFogLevel() string {
+	if x != nil {
+		return x.FogLevel
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetTimeOfDay() string {
+	if x != nil {
+		return x.TimeOfDay
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Weather) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+type SessionMetadata_Driver struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarIdx        uint32                 `protobuf:"varint,1,opt,name=car_idx,json=carIdx,proto3" json:"car_idx,omitempty"`
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	UserId        uint32                 `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TeamName      string                 `protobuf:"bytes,4,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	CarNumber     string                 `protobuf:"bytes,5,opt,name=car_number,json=carNumber,proto3" json:"car_number,omitempty"`
+	CarName       string                 `protobuf:"bytes,6,opt,name=car_name,json=carName,proto3" json:"car_name,omitempty"`
+	CarClass      string                 `protobuf:"bytes,7,opt,name=car_class,json=carClass,proto3" json:"car_class,omitempty"`
+	Irating       int32                  `protobuf:"varint,8,opt,name=irating,proto3" json:"irating,omitempty"`
+	License       string                 `protobuf:"bytes,9,opt,name=license,proto3" json:"license,omitempty"`
+	IsAi          bool                   `protobuf:"varint,10,opt,name=is_ai,json=isAi,proto3" json:"is_ai,omitempty"`
+	IsPaceCar     bool                   `protobuf:"varint,11,opt,name=is_pace_car,json=isPaceCar,proto3" json:"is_pace_car,omitempty"`
+	IsSpectator   bool                   `protobuf:"varint,12,opt,name=is_spectator,json=isSpectator,proto3" json:"is_spectator,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionMetadata_Driver) Reset() {
+	*x = SessionMetadata_Driver{}
+	mi := &file_telemetryTick_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMetadata_Driver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMetadata_Driver) ProtoMessage() {}
+
+func (x *SessionMetadata_Driver) ProtoReflect() protoreflect.Message {
+	mi := &file_telemetryTick_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMetadata_Driver.ProtoReflect.Descriptor instead.
+func (*SessionMetadata_Driver) Descriptor() ([]byte, []int) {
+	return file_telemetryTick_proto_rawDescGZIP(), []int{4, 1}
+}
+
+func (x *SessionMetadata_Driver) GetCarIdx() uint32 {
+	if x != nil {
+		return x.CarIdx
+	}
+	return 0
+}
+
+func (x *SessionMetadata_Driver) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Driver) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *SessionMetadata_Driver) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Driver) GetCarNumber() string {
+	if x != nil {
+		return x.CarNumber
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Driver) GetCarName() string {
+	if x != nil {
+		return x.CarName
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Driver) GetCarClass() string {
+	if x != nil {
+		return x.CarClass
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Driver) GetIrating() int32 {
+	if x != nil {
+		return x.Irating
+	}
+	return 0
+}
+
+func (x *SessionMetadata_Driver) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Driver) GetIsAi() bool {
+	if x != nil {
+		return x.IsAi
+	}
+	return false
+}
+
+func (x *SessionMetadata_Driver) GetIsPaceCar() bool {
+	if x != nil {
+		return x.IsPaceCar
+	}
+	return false
+}
+
+func (x *SessionMetadata_Driver) GetIsSpectator() bool {
+	if x != nil {
+		return x.IsSpectator
+	}
+	return false
+}
+
+type SessionMetadata_Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionNum    uint32                 `protobuf:"varint,1,opt,name=session_num,json=sessionNum,proto3" json:"session_num,omitempty"`
+	SessionType   string                 `protobuf:"bytes,2,opt,name=session_type,json=sessionType,proto3" json:"session_type,omitempty"`
+	SessionName   string                 `protobuf:"bytes,3,opt,name=session_name,json=sessionName,proto3" json:"session_name,omitempty"`
+	SessionLaps   string                 `protobuf:"bytes,4,opt,name=session_laps,json=sessionLaps,proto3" json:"session_laps,omitempty"`
+	SessionTime   string                 `protobuf:"bytes,5,opt,name=session_time,json=sessionTime,proto3" json:"session_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionMetadata_Session) Reset() {
+	*x = SessionMetadata_Session{}
+	mi := &file_telemetryTick_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMetadata_Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMetadata_Session) ProtoMessage() {}
+
+func (x *SessionMetadata_Session) ProtoReflect() protoreflect.Message {
+	mi := &file_telemetryTick_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMetadata_Session.ProtoReflect.Descriptor instead.
+func (*SessionMetadata_Session) Descriptor() ([]byte, []int) {
+	return file_telemetryTick_proto_rawDescGZIP(), []int{4, 2}
+}
+
+func (x *SessionMetadata_Session) GetSessionNum() uint32 {
+	if x != nil {
+		return x.SessionNum
+	}
+	return 0
+}
+
+func (x *SessionMetadata_Session) GetSessionType() string {
+	if x != nil {
+		return x.SessionType
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Session) GetSessionName() string {
+	if x != nil {
+		return x.SessionName
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Session) GetSessionLaps() string {
+	if x != nil {
+		return x.SessionLaps
+	}
+	return ""
+}
+
+func (x *SessionMetadata_Session) GetSessionTime() string {
+	if x != nil {
+		return x.SessionTime
+	}
+	return ""
+}
+
+var File_telemetryTick_proto protoreflect.FileDescriptor
+
+const file_telemetryTick_proto_rawDesc = "" +
+	"\n" +
+	"\x13telemetryTick.proto\x12\x06pubSub\x1a\x1fgoogle/protobuf/timestamp.proto\"\xff\v\n" +
+	"\tTelemetry\x12\x15\n" +
+	"\x06lap_id\x18\x01 \x01(\tR\x05lapId\x12\x14\n" +
+	"\x05speed\x18\x02 \x01(\x01R\x05speed\x12 \n" +
+	"\flap_dist_pct\x18\x03 \x01(\x01R\n" +
+	"lapDistPct\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vsession_num\x18\x05 \x01(\tR\n" +
+	"sessionNum\x12!\n" +
+	"\fsession_type\x18\x06 \x01(\tR\vsessionType\x12!\n" +
+	"\fsession_name\x18\a \x01(\tR\vsessionName\x12!\n" +
+	"\fsession_time\x18\b \x01(\x01R\vsessionTime\x12\x15\n" +
+	"\x06car_id\x18\t \x01(\tR\x05carId\x12\x1d\n" +
+	"\n" +
+	"track_name\x18\n" +
+	" \x01(\tR\ttrackName\x12\x19\n" +
+	"\btrack_id\x18\v \x01(\tR\atrackId\x12\x1b\n" +
+	"\tworker_id\x18\f \x01(\rR\bworkerId\x120\n" +
+	"\x14steering_wheel_angle\x18\r \x01(\x01R\x12steeringWheelAngle\x12.\n" +
+	"\x13player_car_position\x18\x0e \x01(\rR\x11playerCarPosition\x12\x1d\n" +
+	"\n" +
+	"velocity_x\x18\x0f \x01(\x01R\tvelocityX\x12\x1d\n" +
+	"\n" +
+	"velocity_y\x18\x10 \x01(\x01R\tvelocityY\x12\x1d\n" +
+	"\n" +
+	"velocity_z\x18\x11 \x01(\x01R\tvelocityZ\x12\x1d\n" +
+	"\n" +
+	"fuel_level\x18\x12 \x01(\x01R\tfuelLevel\x12\x1a\n" +
+	"\bthrottle\x18\x13 \x01(\x01R\bthrottle\x12\x14\n" +
+	"\x05brake\x18\x14 \x01(\x01R\x05brake\x12\x10\n" +
+	"\x03rpm\x18\x15 \x01(\x01R\x03rpm\x12\x10\n" +
+	"\x03lat\x18\x16 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x17 \x01(\x01R\x03lon\x12\x12\n" +
+	"\x04gear\x18\x18 \x01(\rR\x04gear\x12\x10\n" +
+	"\x03alt\x18\x19 \x01(\x01R\x03alt\x12\x1b\n" +
+	"\tlat_accel\x18\x1a \x01(\x01R\blatAccel\x12\x1d\n" +
+	"\n" +
+	"long_accel\x18\x1b \x01(\x01R\tlongAccel\x12\x1d\n" +
+	"\n" +
+	"vert_accel\x18\x1c \x01(\x01R\tvertAccel\x12\x14\n" +
+	"\x05pitch\x18\x1d \x01(\x01R\x05pitch\x12\x12\n" +
+	"\x04roll\x18\x1e \x01(\x01R\x04roll\x12\x10\n" +
+	"\x03yaw\x18\x1f \x01(\x01R\x03yaw\x12\x1b\n" +
+	"\tyaw_north\x18  \x01(\x01R\byawNorth\x12\x18\n" +
+	"\avoltage\x18! \x01(\x01R\avoltage\x12)\n" +
+	"\x11lap_last_lap_time\x18\" \x01(\x01R\x0elapLastLapTime\x12\x1d\n" +
+	"\n" +
+	"water_temp\x18# \x01(\x01R\twaterTemp\x120\n" +
+	"\x15lap_delta_to_best_lap\x18$ \x01(\x01R\x11lapDeltaToBestLap\x12/\n" +
+	"\x14lap_current_lap_time\x18% \x01(\x01R\x11lapCurrentLapTime\x12\x1f\n" +
+	"\vl_fpressure\x18& \x01(\x01R\n" +
+	"lFpressure\x12\x1f\n" +
+	"\vr_fpressure\x18' \x01(\x01R\n" +
+	"rFpressure\x12\x1f\n" +
+	"\vl_rpressure\x18( \x01(\x01R\n" +
+	"lRpressure\x12\x1f\n" +
+	"\vr_rpressure\x18) \x01(\x01R\n" +
+	"rRpressure\x12\x1a\n" +
+	"\tl_ftemp_m\x18* \x01(\x01R\alFtempM\x12\x1a\n" +
+	"\tr_ftemp_m\x18+ \x01(\x01R\arFtempM\x12\x1a\n" +
+	"\tl_rtemp_m\x18, \x01(\x01R\alRtempM\x12\x1a\n" +
+	"\tr_rtemp_m\x18- \x01(\x01R\arRtempM\x127\n" +
+	"\ttick_time\x18. \x01(\v2\x1a.google.protobuf.TimestampR\btickTime\x12;\n" +
+	"\bchannels\x18/ \x03(\v2\x1f.pubSub.Telemetry.ChannelsEntryR\bchannels\x1a;\n" +
+	"\rChannelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xce\x01\n" +
+	"\x0eTelemetryBatch\x12+\n" +
+	"\arecords\x18\x01 \x03(\v2\x11.pubSub.TelemetryR\arecords\x12\x19\n" +
+	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\tworker_id\x18\x04 \x01(\rR\bworkerId\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xb3\x03\n" +
+	"\fCarTelemetry\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vsession_num\x18\x02 \x01(\tR\n" +
+	"sessionNum\x12\x17\n" +
+	"\acar_idx\x18\x03 \x01(\rR\x06carIdx\x12\x10\n" +
+	"\x03lap\x18\x04 \x01(\x05R\x03lap\x12 \n" +
+	"\flap_dist_pct\x18\x05 \x01(\x01R\n" +
+	"lapDistPct\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x05R\bposition\x12%\n" +
+	"\x0eclass_position\x18\a \x01(\x05R\rclassPosition\x12\x1e\n" +
+	"\von_pit_road\x18\b \x01(\bR\tonPitRoad\x12#\n" +
+	"\rtrack_surface\x18\t \x01(\x05R\ftrackSurface\x12\x19\n" +
+	"\best_time\x18\n" +
+	" \x01(\x01R\aestTime\x12\x17\n" +
+	"\af2_time\x18\v \x01(\x01R\x06f2Time\x12!\n" +
+	"\fsession_time\x18\f \x01(\x01R\vsessionTime\x127\n" +
+	"\ttick_time\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\btickTime\"\xd4\x01\n" +
+	"\x11CarTelemetryBatch\x12.\n" +
+	"\arecords\x18\x01 \x03(\v2\x14.pubSub.CarTelemetryR\arecords\x12\x19\n" +
+	"\bbatch_id\x18\x02 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x03 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\tworker_id\x18\x04 \x01(\rR\bworkerId\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x8a\r\n" +
+	"\x0fSessionMetadata\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\tR\abatchId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\rR\bworkerId\x12?\n" +
+	"\rsession_start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fsessionStart\x12\x1d\n" +
+	"\n" +
+	"track_name\x18\x05 \x01(\tR\ttrackName\x12\x19\n" +
+	"\btrack_id\x18\x06 \x01(\tR\atrackId\x12!\n" +
+	"\ftrack_config\x18\a \x01(\tR\vtrackConfig\x12\x1d\n" +
+	"\n" +
+	"track_city\x18\b \x01(\tR\ttrackCity\x12#\n" +
+	"\rtrack_country\x18\t \x01(\tR\ftrackCountry\x12!\n" +
+	"\ftrack_length\x18\n" +
+	" \x01(\tR\vtrackLength\x12\x1b\n" +
+	"\tseries_id\x18\v \x01(\tR\bseriesId\x12\x1b\n" +
+	"\tseason_id\x18\f \x01(\tR\bseasonId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\r \x01(\tR\teventType\x12\x1a\n" +
+	"\bcategory\x18\x0e \x01(\tR\bcategory\x129\n" +
+	"\aweather\x18\x0f \x01(\v2\x1f.pubSub.SessionMetadata.WeatherR\aweather\x12;\n" +
+	"\bsessions\x18\x10 \x03(\v2\x1f.pubSub.SessionMetadata.SessionR\bsessions\x128\n" +
+	"\adrivers\x18\x11 \x03(\v2\x1e.pubSub.SessionMetadata.DriverR\adrivers\x12$\n" +
+	"\x0eplayer_car_idx\x18\x12 \x01(\rR\fplayerCarIdx\x12\x19\n" +
+	"\bcar_name\x18\x13 \x01(\tR\acarName\x12\x19\n" +
+	"\bcar_path\x18\x14 \x01(\tR\acarPath\x12\x1d\n" +
+	"\n" +
+	"setup_name\x18\x15 \x01(\tR\tsetupName\x12\x1b\n" +
+	"\tcar_setup\x18\x16 \x01(\tR\bcarSetup\x1a\xc8\x02\n" +
+	"\aWeather\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x14\n" +
+	"\x05skies\x18\x02 \x01(\tR\x05skies\x12\x1d\n" +
+	"\n" +
+	"track_temp\x18\x03 \x01(\tR\ttrackTemp\x12\x19\n" +
+	"\bair_temp\x18\x04 \x01(\tR\aairTemp\x12!\n" +
+	"\fair_pressure\x18\x05 \x01(\tR\vairPressure\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\x06 \x01(\tR\twindSpeed\x12\x19\n" +
+	"\bwind_dir\x18\a \x01(\tR\awindDir\x12+\n" +
+	"\x11relative_humidity\x18\b \x01(\tR\x10relativeHumidity\x12\x1b\n" +
+	"\tfog_level\x18\t \x01(\tR\bfogLevel\x12\x1e\n" +
+	"\vtime_of_day\x18\n" +
+	" \x01(\tR\ttimeOfDay\x12\x12\n" +
+	"\x04date\x18\v \x01(\tR\x04date\x1a\xd7\x02\n" +
+	"\x06Driver\x12\x17\n" +
+	"\acar_idx\x18\x01 \x01(\rR\x06carIdx\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\rR\x06userId\x12\x1b\n" +
+	"\tteam_name\x18\x04 \x01(\tR\bteamName\x12\x1d\n" +
+	"\n" +
+	"car_number\x18\x05 \x01(\tR\tcarNumber\x12\x19\n" +
+	"\bcar_name\x18\x06 \x01(\tR\acarName\x12\x1b\n" +
+	"\tcar_class\x18\a \x01(\tR\bcarClass\x12\x18\n" +
+	"\airating\x18\b \x01(\x05R\airating\x12\x18\n" +
+	"\alicense\x18\t \x01(\tR\alicense\x12\x13\n" +
+	"\x05is_ai\x18\n" +
+	" \x01(\bR\x04isAi\x12\x1e\n" +
+	"\vis_pace_car\x18\v \x01(\bR\tisPaceCar\x12!\n" +
+	"\fis_spectator\x18\f \x01(\bR\visSpectator\x1a\xb6\x01\n" +
+	"\aSession\x12\x1f\n" +
+	"\vsession_num\x18\x01 \x01(\rR\n" +
+	"sessionNum\x12!\n" +
+	"\fsession_type\x18\x02 \x01(\tR\vsessionType\x12!\n" +
+	"\fsession_name\x18\x03 \x01(\tR\vsessionName\x12!\n" +
+	"\fsession_laps\x18\x04 \x01(\tR\vsessionLaps\x12!\n" +
+	"\fsession_time\x18\x05 \x01(\tR\vsessionTimeBSZQgithub.com/OJPARKINSON/IRacing-Display/telemetryService/golang/internal/messagingb\x06proto3"
 
 var (
 	file_telemetryTick_proto_rawDescOnce sync.Once
@@ -590,21 +1460,36 @@ func file_telemetryTick_proto_rawDescGZIP() []byte {
 	return file_telemetryTick_proto_rawDescData
 }
 
-var file_telemetryTick_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_telemetryTick_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_telemetryTick_proto_goTypes = []any{
-	(*Telemetry)(nil),             // 0: pubSub.Telemetry
-	(*TelemetryBatch)(nil),        // 1: pubSub.TelemetryBatch
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*Telemetry)(nil),               // 0: pubSub.Telemetry
+	(*TelemetryBatch)(nil),          // 1: pubSub.TelemetryBatch
+	(*CarTelemetry)(nil),            // 2: pubSub.CarTelemetry
+	(*CarTelemetryBatch)(nil),       // 3: pubSub.CarTelemetryBatch
+	(*SessionMetadata)(nil),         // 4: pubSub.SessionMetadata
+	nil,                             // 5: pubSub.Telemetry.ChannelsEntry
+	(*SessionMetadata_Weather)(nil), // 6: pubSub.SessionMetadata.Weather
+	(*SessionMetadata_Driver)(nil),  // 7: pubSub.SessionMetadata.Driver
+	(*SessionMetadata_Session)(nil), // 8: pubSub.SessionMetadata.Session
+	(*timestamppb.Timestamp)(nil),   // 9: google.protobuf.Timestamp
 }
 var file_telemetryTick_proto_depIdxs = []int32{
-	2, // 0: pubSub.Telemetry.tick_time:type_name -> google.protobuf.Timestamp
-	0, // 1: pubSub.TelemetryBatch.records:type_name -> pubSub.Telemetry
-	2, // 2: pubSub.TelemetryBatch.timestamp:type_name -> google.protobuf.Timestamp
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	9,  // 0: pubSub.Telemetry.tick_time:type_name -> google.protobuf.Timestamp
+	5,  // 1: pubSub.Telemetry.channels:type_name -> pubSub.Telemetry.ChannelsEntry
+	0,  // 2: pubSub.TelemetryBatch.records:type_name -> pubSub.Telemetry
+	9,  // 3: pubSub.TelemetryBatch.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 4: pubSub.CarTelemetry.tick_time:type_name -> google.protobuf.Timestamp
+	2,  // 5: pubSub.CarTelemetryBatch.records:type_name -> pubSub.CarTelemetry
+	9,  // 6: pubSub.CarTelemetryBatch.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 7: pubSub.SessionMetadata.session_start:type_name -> google.protobuf.Timestamp
+	6,  // 8: pubSub.SessionMetadata.weather:type_name -> pubSub.SessionMetadata.Weather
+	8,  // 9: pubSub.SessionMetadata.sessions:type_name -> pubSub.SessionMetadata.Session
+	7,  // 10: pubSub.SessionMetadata.drivers:type_name -> pubSub.SessionMetadata.Driver
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_telemetryTick_proto_init() }
@@ -618,7 +1503,7 @@ func file_telemetryTick_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_telemetryTick_proto_rawDesc), len(file_telemetryTick_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
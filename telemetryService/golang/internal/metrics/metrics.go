@@ -37,4 +37,15 @@ var (
 		Help:    "Number of records per batch written to QuestDB",
 		Buckets: prometheus.ExponentialBuckets(100, 2, 10), // 100 to ~102k records
 	})
+
+	// Batches skipped because their batch ID was already applied
+	DuplicateBatchesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "telemetry_duplicate_batches_total",
+		Help: "Total number of redelivered or re-ingested batches skipped before the QuestDB write",
+	})
+
+	DuplicateRecordsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "telemetry_duplicate_records_total",
+		Help: "Total number of records in skipped duplicate batches",
+	})
 )
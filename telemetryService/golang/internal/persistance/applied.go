@@ -0,0 +1,101 @@
+package persistance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ojparkinson/telemetryService/internal/config"
+	qdb "github.com/questdb/go-questdb-client/v4"
+)
+
+// AppliedBatches remembers which batch IDs have been written so a
+// redelivered or re-ingested batch can be skipped before it reaches
+// TelemetryTicks. The IDs are kept in the AppliedBatches table and the
+// recent ones loaded back on startup.
+type AppliedBatches struct {
+	mu  sync.RWMutex
+	ids map[string]struct{}
+}
+
+// AppliedBatch is one batch that was written
+type AppliedBatch struct {
+	BatchID   string
+	SessionID string
+	Records   int
+}
+
+// LoadAppliedBatches reads the batch IDs applied in the last
+// config.AppliedBatchDays days.
+func LoadAppliedBatches(config *config.Config) (*AppliedBatches, error) {
+	applied := &AppliedBatches{ids: make(map[string]struct{})}
+
+	rows, err := ExecuteSelectQuery(fmt.Sprintf(
+		"SELECT batch_id FROM AppliedBatches WHERE timestamp > dateadd('d', -%d, now())",
+		config.AppliedBatchDays), config)
+	if err != nil {
+		return applied, fmt.Errorf("failed to load applied batches: %w", err)
+	}
+
+	for _, row := range rows {
+		if id, ok := row["batch_id"].(string); ok && id != "" {
+			applied.ids[id] = struct{}{}
+		}
+	}
+	return applied, nil
+}
+
+// Seen reports whether the batch has already been written
+func (a *AppliedBatches) Seen(batchID string) bool {
+	if batchID == "" {
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	_, ok := a.ids[batchID]
+	return ok
+}
+
+func (a *AppliedBatches) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.ids)
+}
+
+// Record writes the batch IDs to the AppliedBatches table and remembers
+// them. Call it once the batches' records have been flushed. The IDs are
+// remembered even if the write fails so this process still skips them.
+func (a *AppliedBatches) Record(sender qdb.LineSender, batches []AppliedBatch) error {
+	ctx := context.Background()
+	now := time.Now()
+
+	a.mu.Lock()
+	for _, batch := range batches {
+		if batch.BatchID != "" {
+			a.ids[batch.BatchID] = struct{}{}
+		}
+	}
+	a.mu.Unlock()
+
+	for _, batch := range batches {
+		if batch.BatchID == "" {
+			continue
+		}
+
+		err := sender.Table("AppliedBatches").
+			Symbol("session_id", sanitise(batch.SessionID)).
+			StringColumn("batch_id", batch.BatchID).
+			Int64Column("records", int64(batch.Records)).
+			At(ctx, now)
+		if err != nil {
+			return fmt.Errorf("failed to write applied batch %s: %w", batch.BatchID, err)
+		}
+	}
+
+	if err := sender.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush applied batches: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package persistance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	qdb "github.com/questdb/go-questdb-client/v4"
+)
+
+// fakeSender counts the AppliedBatches rows written. Only the methods
+// Record uses are implemented.
+type fakeSender struct {
+	qdb.LineSender
+	rows     int
+	flushErr error
+}
+
+func (f *fakeSender) Table(name string) qdb.LineSender                  { return f }
+func (f *fakeSender) Symbol(name, val string) qdb.LineSender            { return f }
+func (f *fakeSender) StringColumn(name, val string) qdb.LineSender      { return f }
+func (f *fakeSender) Int64Column(name string, val int64) qdb.LineSender { return f }
+func (f *fakeSender) Flush(ctx context.Context) error                   { return f.flushErr }
+
+func (f *fakeSender) At(ctx context.Context, ts time.Time) error {
+	f.rows++
+	return nil
+}
+
+func TestAppliedBatches(t *testing.T) {
+	testCases := []struct {
+		name     string
+		record   []AppliedBatch
+		flushErr error
+		check    string
+		wantSeen bool
+		wantRows int
+		wantLen  int
+	}{
+		{"Recorded", []AppliedBatch{{BatchID: "batch_ab_0_0_500", Records: 500}}, nil, "batch_ab_0_0_500", true, 1, 1},
+		{"OtherOffset", []AppliedBatch{{BatchID: "batch_ab_0_0_500", Records: 500}}, nil, "batch_ab_0_500_500", false, 1, 1},
+		{"OtherCount", []AppliedBatch{{BatchID: "batch_ab_0_0_500", Records: 500}}, nil, "batch_ab_0_0_250", false, 1, 1},
+		{"EmptyIDNeverSeen", []AppliedBatch{{BatchID: "", Records: 500}}, nil, "", false, 0, 0},
+		{"RememberedWhenFlushFails", []AppliedBatch{{BatchID: "cars_ab_0_0_60", Records: 60}}, errors.New("questdb down"), "cars_ab_0_0_60", true, 1, 1},
+		{"Several", []AppliedBatch{{BatchID: "batch_ab_0_0_500"}, {BatchID: "session_ab_0"}}, nil, "session_ab_0", true, 2, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			applied := &AppliedBatches{ids: make(map[string]struct{})}
+			sender := &fakeSender{flushErr: tc.flushErr}
+
+			err := applied.Record(sender, tc.record)
+			if (err != nil) != (tc.flushErr != nil) {
+				t.Errorf("Record error = %v, want flush error %v", err, tc.flushErr)
+			}
+
+			if got := applied.Seen(tc.check); got != tc.wantSeen {
+				t.Errorf("Seen(%q) = %v, want %v", tc.check, got, tc.wantSeen)
+			}
+			if sender.rows != tc.wantRows {
+				t.Errorf("wrote %d rows, want %d", sender.rows, tc.wantRows)
+			}
+			if got := applied.Len(); got != tc.wantLen {
+				t.Errorf("Len = %d, want %d", got, tc.wantLen)
+			}
+		})
+	}
+}
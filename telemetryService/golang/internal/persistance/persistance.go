@@ -2,9 +2,11 @@ package persistance
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,7 +19,7 @@ func WriteBatch(sender qdb.LineSender, records []*messaging.Telemetry) error {
 	const flushInterval = 100000
 
 	for i, record := range records {
-		sender.Table("TelemetryTicks").
+		row := sender.Table("TelemetryTicks").
 			Symbol("session_id", sanitise(record.SessionId)).
 			Symbol("track_name", sanitise(record.TrackName)).
 			Symbol("track_id", sanitise(record.TrackId)).
@@ -61,8 +63,16 @@ func WriteBatch(sender qdb.LineSender, records []*messaging.Telemetry) error {
 			Float64Column("lFtempM", validateDouble(record.LFtempM)).
 			Float64Column("rFtempM", validateDouble(record.RFtempM)).
 			Float64Column("lRtempM", validateDouble(record.LRtempM)).
-			Float64Column("rRtempM", validateDouble(record.RRtempM)).
-			At(ctx, tickTime(record))
+			Float64Column("rRtempM", validateDouble(record.RRtempM))
+
+		// Channels from the ingest manifest, QuestDB adds new columns on first write
+		for name, value := range record.Channels {
+			if column, ok := channelColumn(name); ok {
+				row = row.Float64Column(column, validateDouble(value))
+			}
+		}
+
+		row.At(ctx, tickTime(record))
 
 		// Flush every 10K records to keep memory and network packets reasonable
 		if (i+1)%flushInterval == 0 {
@@ -82,6 +92,129 @@ func WriteBatch(sender qdb.LineSender, records []*messaging.Telemetry) error {
 	return nil
 }
 
+// WriteCarBatch writes the per-car CarIdx records, one row per car per tick
+func WriteCarBatch(sender qdb.LineSender, records []*messaging.CarTelemetry) error {
+	ctx := context.Background()
+
+	for _, record := range records {
+		var ts time.Time
+		if record.TickTime != nil {
+			ts = record.TickTime.AsTime()
+		} else {
+			ts = time.Now()
+		}
+
+		sender.Table("CarTelemetry").
+			Symbol("session_id", sanitise(record.SessionId)).
+			Symbol("session_num", sanitise(record.SessionNum)).
+			Symbol("car_idx", strconv.Itoa(int(record.CarIdx))).
+			Int64Column("lap", int64(record.Lap)).
+			Int64Column("position", int64(record.Position)).
+			Int64Column("class_position", int64(record.ClassPosition)).
+			Int64Column("track_surface", int64(record.TrackSurface)).
+			BoolColumn("on_pit_road", record.OnPitRoad).
+			Float64Column("lap_dist_pct", validateDouble(record.LapDistPct)).
+			Float64Column("est_time", validateDouble(record.EstTime)).
+			Float64Column("f2_time", validateDouble(record.F2Time)).
+			Float64Column("session_time", validateDouble(record.SessionTime)).
+			At(ctx, ts)
+	}
+
+	if err := sender.Flush(ctx); err != nil {
+		return fmt.Errorf("car batch flush failed: %w", err)
+	}
+
+	return nil
+}
+
+// WriteSessionMetadata writes the once per group session row. The driver
+// roster and session list are stored as JSON alongside the car setup.
+func WriteSessionMetadata(sender qdb.LineSender, meta *messaging.SessionMetadata) error {
+	ctx := context.Background()
+
+	drivers, err := json.Marshal(meta.Drivers)
+	if err != nil {
+		return fmt.Errorf("failed to encode driver roster: %w", err)
+	}
+
+	sessions, err := json.Marshal(meta.Sessions)
+	if err != nil {
+		return fmt.Errorf("failed to encode sessions: %w", err)
+	}
+
+	weather := meta.Weather
+	if weather == nil {
+		weather = &messaging.SessionMetadata_Weather{}
+	}
+
+	start := time.Now()
+	if meta.SessionStart != nil {
+		start = meta.SessionStart.AsTime()
+	}
+
+	sender.Table("SessionMetadata").
+		Symbol("session_id", sanitise(meta.SessionId)).
+		Symbol("track_name", sanitise(meta.TrackName)).
+		Symbol("track_id", sanitise(meta.TrackId)).
+		Symbol("car_name", sanitise(meta.CarName)).
+		StringColumn("track_config", meta.TrackConfig).
+		StringColumn("track_city", meta.TrackCity).
+		StringColumn("track_country", meta.TrackCountry).
+		StringColumn("track_length", meta.TrackLength).
+		StringColumn("series_id", meta.SeriesId).
+		StringColumn("season_id", meta.SeasonId).
+		StringColumn("event_type", meta.EventType).
+		StringColumn("category", meta.Category).
+		StringColumn("weather_type", weather.Type).
+		StringColumn("skies", weather.Skies).
+		StringColumn("track_temp", weather.TrackTemp).
+		StringColumn("air_temp", weather.AirTemp).
+		StringColumn("air_pressure", weather.AirPressure).
+		StringColumn("wind_speed", weather.WindSpeed).
+		StringColumn("wind_dir", weather.WindDir).
+		StringColumn("relative_humidity", weather.RelativeHumidity).
+		StringColumn("fog_level", weather.FogLevel).
+		StringColumn("time_of_day", weather.TimeOfDay).
+		StringColumn("event_date", weather.Date).
+		Int64Column("player_car_idx", int64(meta.PlayerCarIdx)).
+		StringColumn("car_path", meta.CarPath).
+		StringColumn("setup_name", meta.SetupName).
+		StringColumn("car_setup", meta.CarSetup).
+		StringColumn("drivers", string(drivers)).
+		StringColumn("sessions", string(sessions)).
+		At(ctx, start)
+
+	if err := sender.Flush(ctx); err != nil {
+		return fmt.Errorf("session metadata flush failed: %w", err)
+	}
+
+	return nil
+}
+
+// ChannelColumnPrefix is put in front of every manifest channel column so
+// they can never collide with the fixed columns. ingest's QuestDB sink
+// writes the same columns through channels.ColumnPrefix, keep them equal.
+const ChannelColumnPrefix = "ch_"
+
+// channelColumn prefixes manifest channels and rejects names that are not
+// plain IBT identifiers.
+func channelColumn(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(i > 0 && (isDigit || c == '_')) {
+			return "", false
+		}
+	}
+
+	return ChannelColumnPrefix + name, true
+}
+
 func tickTime(record *messaging.Telemetry) time.Time {
 	if record.TickTime != nil {
 		return record.TickTime.AsTime()
@@ -3,6 +3,7 @@ package persistance
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ojparkinson/telemetryService/internal/config"
 	"github.com/ojparkinson/telemetryService/internal/messaging"
@@ -12,49 +13,77 @@ type QueryExecutor struct {
 	Config *config.Config
 }
 
+// SessionFilter narrows session and lap queries. Empty fields match
+// everything, so practice, qualifying and race data are all returned.
+type SessionFilter struct {
+	// SessionType matches session_type or session_name case-insensitively,
+	// e.g. "practice", "qualify", "race" or "lone qualify"
+	SessionType string
+	// SessionNum picks one session within a subsession, 0 is usually practice
+	SessionNum string
+}
+
+func (f SessionFilter) where() string {
+	var clauses []string
+	if f.SessionType != "" {
+		sessionType := quote(strings.ToLower(f.SessionType))
+		clauses = append(clauses, fmt.Sprintf("(lower(session_type) = %s OR lower(session_name) = %s)", sessionType, sessionType))
+	}
+	if f.SessionNum != "" {
+		clauses = append(clauses, fmt.Sprintf("session_num = %s", quote(f.SessionNum)))
+	}
+
+	if len(clauses) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(clauses, " AND ")
+}
+
 func (s *QueryExecutor) QuerySession(ctx context.Context, sessionID string) ([]map[string]interface{}, error) {
 	query := `
 		SELECT DISTINCT session_id, track_name, session_name,
 					MAX(lap_id) as max_lap_id,
 					MAX(timestamp) as last_updated
 		FROM TelemetryTicks
-		WHERE session_name = 'RACE' AND lap_id > 0
+		WHERE lap_id > 0
 		GROUP BY session_id, track_name, session_name
 		ORDER BY last_updated DESC
 	`
 	return ExecuteSelectQuery(query, s.Config)
 }
 
-func (s *QueryExecutor) QuerySessions(ctx context.Context) ([]map[string]interface{}, error) {
-	query := `
-		SELECT DISTINCT session_id, track_name, session_name,
+// QuerySessions lists one row per session within each subsession, so a
+// practice, qualifying and race in the same file are listed separately.
+func (s *QueryExecutor) QuerySessions(ctx context.Context, filter SessionFilter) ([]map[string]interface{}, error) {
+	query := fmt.Sprintf(`
+		SELECT session_id, track_name, session_num, session_type, session_name,
                 MAX(lap_id) as max_lap_id,
                 MAX(timestamp) as last_updated
 		FROM TelemetryTicks
-		WHERE session_name = 'RACE' AND lap_id > 0
-		GROUP BY session_id, track_name, session_name
+		WHERE lap_id > 0%s
+		GROUP BY session_id, track_name, session_num, session_type, session_name
 		ORDER BY last_updated DESC
-	`
+	`, filter.where())
 	return ExecuteSelectQuery(query, s.Config)
 }
 
-func (s *QueryExecutor) QueryLaps(ctx context.Context, sessionID string) ([]map[string]interface{}, error) {
+func (s *QueryExecutor) QueryLaps(ctx context.Context, sessionID string, filter SessionFilter) ([]map[string]interface{}, error) {
 	query := fmt.Sprintf(`
 		SELECT DISTINCT lap_id 
 		FROM TelemetryTicks 
-		WHERE session_id = %s
+		WHERE session_id = %s%s
 		ORDER BY lap_id ASC
-	`, sessionID)
+	`, quote(sessionID), filter.where())
 
 	return ExecuteSelectQuery(query, s.Config)
 }
 
-func (s *QueryExecutor) QueryLap(ctx context.Context, sessionID string, lapID string) ([]messaging.Telemetry, error) {
+func (s *QueryExecutor) QueryLap(ctx context.Context, sessionID string, lapID string, filter SessionFilter) ([]messaging.Telemetry, error) {
 	query := fmt.Sprintf(`
 		SELECT * FROM TelemetryTicks
-		WHERE session_name = 'RACE' AND session_id = '%s' AND lap_id = '%s'
+		WHERE session_id = %s AND lap_id = %s%s
 		ORDER BY timestamp ASC
-	`, sessionID, lapID)
+	`, quote(sessionID), quote(lapID), filter.where())
 
 	rows, err := ExecuteSelectQuery(query, s.Config)
 	if err != nil {
@@ -89,3 +118,8 @@ func (s *QueryExecutor) QueryGeneralLap(ctx context.Context, sessionID string, l
 
 	return points, nil
 }
+
+// quote renders a value as a QuestDB string literal
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -78,6 +78,91 @@ func (s *Schema) CreateTableHTTP() error {
 	return err
 }
 
+// CreateCarTableHTTP creates the per-car table fed by the CarIdx arrays,
+// keyed by session and car index.
+func (s *Schema) CreateCarTableHTTP() error {
+	sql := `
+		    CREATE TABLE IF NOT EXISTS CarTelemetry (
+                session_id SYMBOL CAPACITY 50000 INDEX,
+                session_num SYMBOL CAPACITY 20,
+                car_idx SYMBOL CAPACITY 64 INDEX,
+                lap INT,
+                position INT,
+                class_position INT,
+                track_surface INT,
+                on_pit_road BOOLEAN,
+                lap_dist_pct DOUBLE,
+                est_time DOUBLE,
+                f2_time DOUBLE,
+                session_time DOUBLE,
+                timestamp TIMESTAMP
+            ) TIMESTAMP(timestamp) PARTITION BY DAY
+            WAL
+            DEDUP UPSERT KEYS(timestamp, session_id, car_idx);
+	`
+	_, err := ExecuteSelectQuery(sql, s.config)
+	return err
+}
+
+// CreateSessionTableHTTP creates the table for SessionMetadata, one row per
+// session group with the conditions, roster and the player's setup.
+func (s *Schema) CreateSessionTableHTTP() error {
+	sql := `
+		    CREATE TABLE IF NOT EXISTS SessionMetadata (
+                session_id SYMBOL CAPACITY 50000 INDEX,
+                track_name SYMBOL CAPACITY 100 INDEX,
+                track_id SYMBOL CAPACITY 100,
+                car_name SYMBOL CAPACITY 1000 INDEX,
+                track_config VARCHAR,
+                track_city VARCHAR,
+                track_country VARCHAR,
+                track_length VARCHAR,
+                series_id VARCHAR,
+                season_id VARCHAR,
+                event_type VARCHAR,
+                category VARCHAR,
+                weather_type VARCHAR,
+                skies VARCHAR,
+                track_temp VARCHAR,
+                air_temp VARCHAR,
+                air_pressure VARCHAR,
+                wind_speed VARCHAR,
+                wind_dir VARCHAR,
+                relative_humidity VARCHAR,
+                fog_level VARCHAR,
+                time_of_day VARCHAR,
+                event_date VARCHAR,
+                player_car_idx INT,
+                car_path VARCHAR,
+                setup_name VARCHAR,
+                car_setup VARCHAR,
+                drivers VARCHAR,
+                sessions VARCHAR,
+                timestamp TIMESTAMP
+            ) TIMESTAMP(timestamp) PARTITION BY MONTH
+            WAL
+            DEDUP UPSERT KEYS(timestamp, session_id);
+	`
+	_, err := ExecuteSelectQuery(sql, s.config)
+	return err
+}
+
+// CreateAppliedBatchTableHTTP creates the record of batch IDs that have
+// been written, used to skip redelivered and re-ingested batches.
+func (s *Schema) CreateAppliedBatchTableHTTP() error {
+	sql := `
+		    CREATE TABLE IF NOT EXISTS AppliedBatches (
+                batch_id VARCHAR,
+                session_id SYMBOL CAPACITY 50000 INDEX,
+                records INT,
+                timestamp TIMESTAMP
+            ) TIMESTAMP(timestamp) PARTITION BY DAY
+            WAL;
+	`
+	_, err := ExecuteSelectQuery(sql, s.config)
+	return err
+}
+
 func (s *Schema) AddIndexes() error {
 	indexes := []string{
 		"ALTER TABLE TelemetryTicks ADD INDEX session_lap_idx (session_id, lap_id);",
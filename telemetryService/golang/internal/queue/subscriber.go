@@ -9,18 +9,21 @@ import (
 	"github.com/ojparkinson/telemetryService/internal/messaging"
 	"github.com/ojparkinson/telemetryService/internal/metrics"
 	"github.com/ojparkinson/telemetryService/internal/persistance"
+	qdb "github.com/questdb/go-questdb-client/v4"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"google.golang.org/protobuf/proto"
 )
 
 type Subscriber struct {
 	senderPool *persistance.SenderPool
+	applied    *persistance.AppliedBatches
 	stopChan   chan struct{}
 }
 
-func NewSubscriber(pool *persistance.SenderPool) *Subscriber {
+func NewSubscriber(pool *persistance.SenderPool, applied *persistance.AppliedBatches) *Subscriber {
 	return &Subscriber{
 		senderPool: pool,
+		applied:    applied,
 		stopChan:   make(chan struct{}),
 	}
 }
@@ -37,11 +40,17 @@ type workResult struct {
 	err         error
 }
 
+// batchItem is one delivery, either a tick batch or a car batch
 type batchItem struct {
 	batch       *messaging.TelemetryBatch
+	cars        *messaging.CarTelemetryBatch
 	deliveryTag uint64
 }
 
+func (b batchItem) records() int {
+	return len(b.batch.GetRecords()) + len(b.cars.GetRecords())
+}
+
 func (m *Subscriber) Subscribe(config *config.Config) {
 	var conn *amqp.Connection
 	var err error
@@ -79,6 +88,16 @@ func (m *Subscriber) Subscribe(config *config.Config) {
 		"telemetry_topic", false, nil)
 	failOnError(errs, "Failed to bind to queue")
 
+	errs = channel.QueueBind("telemetry_queue",
+		"telemetry.cars",
+		"telemetry_topic", false, nil)
+	failOnError(errs, "Failed to bind car stream to queue")
+
+	errs = channel.QueueBind("telemetry_queue",
+		"telemetry.session",
+		"telemetry_topic", false, nil)
+	failOnError(errs, "Failed to bind session metadata to queue")
+
 	msgs, err := channel.Consume("telemetry_queue", "", false, false, false, false, nil)
 	failOnError(err, "Failed to consume queue")
 
@@ -90,6 +109,31 @@ func (m *Subscriber) Subscribe(config *config.Config) {
 	go m.processBatches(batchChan, channel)
 
 	for event := range msgs {
+		switch event.RoutingKey {
+		case "telemetry.cars":
+			cars := &messaging.CarTelemetryBatch{}
+			if !decode(event, cars) || m.skipApplied(event, cars.BatchId, len(cars.Records)) {
+				continue
+			}
+
+			batchChan <- batchItem{
+				cars:        cars,
+				deliveryTag: event.DeliveryTag,
+			}
+			continue
+		case "telemetry.session":
+			meta := &messaging.SessionMetadata{}
+			if !decode(event, meta) || m.skipApplied(event, meta.BatchId, 1) {
+				continue
+			}
+
+			applied := persistance.AppliedBatch{BatchID: meta.BatchId, SessionID: meta.SessionId, Records: 1}
+			m.writeDirect(event, applied, func(sender qdb.LineSender) error {
+				return persistance.WriteSessionMetadata(sender, meta)
+			})
+			continue
+		}
+
 		batch := &messaging.TelemetryBatch{}
 		err := proto.Unmarshal(event.Body, batch)
 		if err != nil {
@@ -99,6 +143,10 @@ func (m *Subscriber) Subscribe(config *config.Config) {
 			continue
 		}
 
+		if m.skipApplied(event, batch.BatchId, len(batch.Records)) {
+			continue
+		}
+
 		// fmt.Printf("Received batch: session=%v, records=%d\n", batch.SessionId, len(batch.Records))
 		receivedCount++
 
@@ -194,17 +242,17 @@ func (m *Subscriber) processBatches(batchChan chan batchItem, channel *amqp.Chan
 			return
 
 		case item := <-batchChan:
-			newRecordCount := totalRecords + len(item.batch.Records)
+			newRecordCount := totalRecords + item.records()
 			if newRecordCount > maxRecordsPerBatch && len(batchBuffer) > 0 {
 				sendToWorkers(batchBuffer)
 				batchBuffer = []batchItem{item}
-				totalRecords = len(item.batch.Records)
+				totalRecords = item.records()
 				timer.Reset(batchTimeout)
 				continue
 			}
 
 			batchBuffer = append(batchBuffer, item)
-			totalRecords += len(item.batch.Records)
+			totalRecords += item.records()
 
 			if len(batchBuffer) >= targetBatchSize {
 				sendToWorkers(batchBuffer)
@@ -238,12 +286,12 @@ func (m *Subscriber) processBatches(batchChan chan batchItem, channel *amqp.Chan
 func CollectValidRecords(items []batchItem) []*messaging.Telemetry {
 	totalRecords := 0
 	for _, item := range items {
-		totalRecords += len(item.batch.Records)
+		totalRecords += len(item.batch.GetRecords())
 	}
 
 	validRecords := make([]*messaging.Telemetry, 0, totalRecords)
 	for _, item := range items {
-		for _, record := range item.batch.Records {
+		for _, record := range item.batch.GetRecords() {
 			if IsValidRecord(record) {
 				validRecords = append(validRecords, record)
 			}
@@ -252,6 +300,15 @@ func CollectValidRecords(items []batchItem) []*messaging.Telemetry {
 	return validRecords
 }
 
+// collectCarRecords gathers the per-car records from the car batch items
+func collectCarRecords(items []batchItem) []*messaging.CarTelemetry {
+	var records []*messaging.CarTelemetry
+	for _, item := range items {
+		records = append(records, item.cars.GetRecords()...)
+	}
+	return records
+}
+
 func (m *Subscriber) flushBatches(items []batchItem, channel *amqp.Channel) {
 	validRecords := CollectValidRecords(items)
 
@@ -303,6 +360,61 @@ func (m *Subscriber) flushBatches(items []batchItem, channel *amqp.Channel) {
 	}
 }
 
+// skipApplied acks a delivery whose batch was already written by an
+// earlier delivery or ingest of the same file
+func (m *Subscriber) skipApplied(event amqp.Delivery, batchID string, records int) bool {
+	if !m.applied.Seen(batchID) {
+		return false
+	}
+
+	metrics.DuplicateBatchesTotal.Inc()
+	metrics.DuplicateRecordsTotal.Add(float64(records))
+	log.Printf("Skipping batch %s, already applied", batchID)
+	if err := event.Ack(false); err != nil {
+		log.Printf("Failed to ACK duplicate batch %s: %v", batchID, err)
+	}
+	return true
+}
+
+// decode unmarshals a car batch or session metadata message, a message
+// that cannot be read is dropped
+func decode(event amqp.Delivery, msg proto.Message) bool {
+	if err := proto.Unmarshal(event.Body, msg); err != nil {
+		fmt.Printf("error unmarshalling %s message: %v\n", event.RoutingKey, err)
+		if err := event.Nack(false, false); err != nil {
+			fmt.Println("Failed to nack message: ", err)
+		}
+		return false
+	}
+	return true
+}
+
+// writeDirect persists a session metadata message straight away and records
+// it as applied. There is one per session so it skips the worker pool.
+func (m *Subscriber) writeDirect(event amqp.Delivery, applied persistance.AppliedBatch, write func(qdb.LineSender) error) {
+	sender := m.senderPool.Get()
+	err := write(sender)
+	if err == nil {
+		if err := m.applied.Record(sender, []persistance.AppliedBatch{applied}); err != nil {
+			log.Printf("%v, %s message %s will not be skipped after a restart", err, event.RoutingKey, applied.BatchID)
+		}
+	}
+	m.senderPool.Return(sender)
+
+	if err != nil {
+		metrics.DBWriteErrors.Inc()
+		log.Printf("Write failed for %s message %s: %v", event.RoutingKey, event.MessageId, err)
+		if err := event.Nack(false, true); err != nil {
+			log.Printf("Failed to NACK %s message: %v", event.RoutingKey, err)
+		}
+		return
+	}
+
+	if err := event.Ack(false); err != nil {
+		log.Printf("Failed to ACK %s message: %v", event.RoutingKey, err)
+	}
+}
+
 func (m *Subscriber) Stop() {
 	close(m.stopChan)
 }
@@ -328,11 +440,21 @@ func (m *Subscriber) worker(id int, workChan <-chan workItem) {
 		sender := m.senderPool.Get()
 
 		validRecords := CollectValidRecords(work.batchItems)
+		carRecords := collectCarRecords(work.batchItems)
 
 		start := time.Now()
 		err := persistance.WriteBatch(sender, validRecords)
+		if err == nil && len(carRecords) > 0 {
+			err = persistance.WriteCarBatch(sender, carRecords)
+		}
 		duration := time.Since(start)
 
+		if err == nil {
+			if err := m.applied.Record(sender, appliedBatches(work.batchItems)); err != nil {
+				log.Printf("Worker %d: %v, the batches will not be skipped after a restart", id, err)
+			}
+		}
+
 		m.senderPool.Return(sender)
 
 		work.resultChan <- workResult{
@@ -344,12 +466,32 @@ func (m *Subscriber) worker(id int, workChan <-chan workItem) {
 		metrics.DBWriteDuration.Observe(duration.Seconds())
 		if err == nil {
 			metrics.RecordsWrittenTotal.Add(float64(len(validRecords)))
-			log.Printf("Worker %d: wrote %d records in %v", id, len(validRecords), duration)
+			log.Printf("Worker %d: wrote %d records and %d car records in %v", id, len(validRecords), len(carRecords), duration)
 		} else {
 			metrics.DBWriteErrors.Inc()
-			log.Printf("Worker %d: write failed for %d records in %v: %v", id, len(validRecords), duration, err)
+			log.Printf("Worker %d: write failed for %d records and %d car records in %v: %v", id, len(validRecords), len(carRecords), duration, err)
+		}
+	}
+}
+
+func appliedBatches(items []batchItem) []persistance.AppliedBatch {
+	batches := make([]persistance.AppliedBatch, len(items))
+	for i, item := range items {
+		if item.cars != nil {
+			batches[i] = persistance.AppliedBatch{
+				BatchID:   item.cars.BatchId,
+				SessionID: item.cars.SessionId,
+				Records:   len(item.cars.Records),
+			}
+			continue
+		}
+		batches[i] = persistance.AppliedBatch{
+			BatchID:   item.batch.BatchId,
+			SessionID: item.batch.SessionId,
+			Records:   len(item.batch.Records),
 		}
 	}
+	return batches
 }
 
 func (m *Subscriber) resultHandler(resultChan <-chan workResult, channel *amqp.Channel) {